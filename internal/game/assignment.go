@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/juan-medina/classical-concepts-2-trainer/internal/shapes"
+)
+
+// how the assignment of the round is shown
+type assignmentDisplay string
+
+const (
+	TextAssignment  assignmentDisplay = "text"
+	IconsAssignment assignmentDisplay = "icons"
+	// only the icons, to practice reading them as in the fight
+	QuizAssignment assignmentDisplay = "quiz"
+)
+
+var assignmentDisplays = []assignmentDisplay{TextAssignment, IconsAssignment, QuizAssignment}
+
+var assignmentMessages = map[assignmentDisplay]message{
+	TextAssignment:  TextAssignmentMessage,
+	IconsAssignment: IconsAssignmentMessage,
+	QuizAssignment:  QuizAssignmentMessage,
+}
+
+var (
+	iconFrame      = color.RGBA64{0xCCCC, 0xAAAA, 0x5555, 0xFFFF}
+	iconBackground = color.RGBA64{0x3333, 0x3333, 0x3333, 0xFFFF}
+)
+
+// DrawDebuffIcon draws the alpha or beta debuff as a framed icon with its
+// symbol inside
+func (g game) DrawDebuffIcon(screen *ebiten.Image, x, y, size float32, symbol TileState) {
+	shapes.RoundedPolygon(x, y, size*1.4, 4, 45, size/4).Draw(screen, shapes.Style{
+		Fill:        iconBackground,
+		FillEdge:    black,
+		Stroke:      iconFrame,
+		StrokeWidth: size / 8,
+		AntiAlias:   true,
+	})
+	g.DrawSymbol(g.batch, x, y, size*0.6, 0, symbol)
+	g.batch.Flush(screen)
+}
+
+// DrawHeadmarker draws the marker over the head of the player, an arrow down
+// in the color of the column
+func (g game) DrawHeadmarker(screen *ebiten.Image, x, y, size float32, column int) {
+	shapes.Arrow(x, y-size, x, y+size, size*0.7, size).Draw(screen, shapes.Style{
+		Fill:        g.columnColor(column),
+		FillEdge:    g.waymarkColor(column),
+		Stroke:      black,
+		StrokeWidth: size / 10,
+		Glow:        g.columnColor(column),
+		GlowWidth:   size / 4,
+		AntiAlias:   true,
+	})
+}
+
+// DrawAssignment draws the debuff icon and the headmarker of the round
+// side by side, in shape only mode the headmarker has its waymark next to it
+// as the color is not enough to tell the column
+func (g game) DrawAssignment(screen *ebiten.Image, y float32) {
+	size := g.layout.tileRadius * 0.8
+	offset := g.layout.tileRadius * 1.2
+	g.DrawDebuffIcon(screen, g.layout.objectiveX-offset, y, size, g.symbolObjective)
+	g.DrawHeadmarker(screen, g.layout.objectiveX+offset, y, size, g.columnObjective)
+	if g.settings.ShapeOnly {
+		g.DrawWaymark(screen, g.layout.objectiveX+offset*2, y, size/2, g.columnObjective)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+const (
+	SAMPLE_RATE = 44100
+	MAX_VOLUME  = 10
+	// seconds of attack and release of each note, so they do not click
+	ATTACK  = 0.005
+	RELEASE = 0.05
+	// the countdown sound is played for each of these last seconds
+	COUNTDOWN_SECONDS = 3
+)
+
+type sound int
+
+const (
+	RoundStartSound sound = iota
+	SelectSound
+	CountdownSound
+	SuccessSound
+	FailureSound
+)
+
+// note is a tone, a frequency of 0 is a silence
+type note struct {
+	frequency float64
+	duration  float64
+}
+
+var soundNotes = map[sound][]note{
+	RoundStartSound: {{523.25, 0.12}, {783.99, 0.2}},
+	SelectSound:     {{1046.5, 0.06}},
+	CountdownSound:  {{880, 0.1}},
+	SuccessSound:    {{523.25, 0.1}, {659.25, 0.1}, {783.99, 0.1}, {1046.5, 0.3}},
+	FailureSound:    {{392, 0.15}, {0, 0.05}, {311.13, 0.15}, {0, 0.05}, {233.08, 0.4}},
+}
+
+// synthesize returns the notes as 16 bits little endian stereo PCM
+func synthesize(notes []note) []byte {
+	var pcm []byte
+	for _, n := range notes {
+		samples := int(n.duration * SAMPLE_RATE)
+		for i := 0; i < samples; i++ {
+			t := float64(i) / SAMPLE_RATE
+			envelope := math.Min(1, math.Min(t/ATTACK, (n.duration-t)/RELEASE))
+			// a bit of the second harmonic makes it less harsh than a pure sine
+			value := math.Sin(2*math.Pi*n.frequency*t)*0.8 + math.Sin(4*math.Pi*n.frequency*t)*0.2
+			sample := int16(value * envelope * 0.5 * math.MaxInt16)
+			pcm = append(pcm, byte(sample), byte(sample>>8), byte(sample), byte(sample>>8))
+		}
+	}
+	return pcm
+}
+
+type sounds struct {
+	context *audio.Context
+	pcm     map[sound][]byte
+	// keep the last player of each sound, so it is not collected while playing
+	players map[sound]*audio.Player
+}
+
+func newSounds() *sounds {
+	s := &sounds{
+		context: audio.NewContext(SAMPLE_RATE),
+		pcm:     map[sound][]byte{},
+		players: map[sound]*audio.Player{},
+	}
+	for id, notes := range soundNotes {
+		s.pcm[id] = synthesize(notes)
+	}
+	return s
+}
+
+func (g *game) PlaySound(id sound) {
+	if g.settings.Mute || g.settings.Volume == 0 {
+		return
+	}
+	player := g.sounds.context.NewPlayerFromBytes(g.sounds.pcm[id])
+	player.SetVolume(float64(g.settings.Volume) / MAX_VOLUME)
+	player.Play()
+	g.sounds.players[id] = player
+}
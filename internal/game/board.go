@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+// board holds the tiles being played, indexed by row and column
+type board [][]tile
+
+func newBoard(rows, cols int) board {
+	b := make(board, rows)
+	for r := range b {
+		b[r] = make([]tile, cols)
+	}
+	return b
+}
+
+// placedTile is a tile that starts with a state, column first as in SetTile
+type placedTile struct {
+	column, row int
+	state       TileState
+}
+
+// boardDefinition describes a board: its size, the holes that have no tile,
+// the column each marker stands over, its waymark and the symbols a round can
+// start with
+type boardDefinition struct {
+	rows, cols    int
+	holes         []BoardPosition
+	markerColumns [NUM_MARKERS]int
+	waymarks      [NUM_MARKERS]waymark
+	setups        [][]placedTile
+}
+
+var classicalConcepts = boardDefinition{
+	rows: 5,
+	cols: 7,
+	holes: []BoardPosition{
+		{row: 1, column: 1}, {row: 3, column: 1},
+		{row: 1, column: 3}, {row: 3, column: 3},
+		{row: 1, column: 5}, {row: 3, column: 5},
+	},
+	markerColumns: [NUM_MARKERS]int{0, 2, 4, 6},
+	waymarks:      letterWaymarks,
+	setups: [][]placedTile{
+		{
+			{0, 0, BetaTile}, {0, 2, CenterTile}, {0, 4, AlphaTile},
+			{2, 0, CenterTile}, {2, 2, AlphaTile}, {2, 4, BetaTile},
+			{4, 0, BetaTile}, {4, 2, BetaTile}, {4, 4, CenterTile},
+			{6, 0, AlphaTile}, {6, 2, CenterTile}, {6, 4, AlphaTile},
+		},
+		{
+			{0, 0, AlphaTile}, {0, 2, CenterTile}, {0, 4, BetaTile},
+			{2, 0, CenterTile}, {2, 2, BetaTile}, {2, 4, BetaTile},
+			{4, 0, AlphaTile}, {4, 2, AlphaTile}, {4, 4, CenterTile},
+			{6, 0, AlphaTile}, {6, 2, CenterTile}, {6, 4, BetaTile},
+		},
+		{
+			{0, 0, AlphaTile}, {0, 2, CenterTile}, {0, 4, BetaTile},
+			{2, 0, BetaTile}, {2, 2, CenterTile}, {2, 4, BetaTile},
+			{4, 0, AlphaTile}, {4, 2, AlphaTile}, {4, 4, CenterTile},
+			{6, 0, CenterTile}, {6, 2, BetaTile}, {6, 4, AlphaTile},
+		},
+		{
+			{0, 0, AlphaTile}, {0, 2, CenterTile}, {0, 4, BetaTile},
+			{2, 0, AlphaTile}, {2, 2, AlphaTile}, {2, 4, CenterTile},
+			{4, 0, CenterTile}, {4, 2, BetaTile}, {4, 4, BetaTile},
+			{6, 0, AlphaTile}, {6, 2, CenterTile}, {6, 4, BetaTile},
+		},
+		{
+			{0, 0, CenterTile}, {0, 2, AlphaTile}, {0, 4, BetaTile},
+			{2, 0, BetaTile}, {2, 2, BetaTile}, {2, 4, CenterTile},
+			{4, 0, AlphaTile}, {4, 2, CenterTile}, {4, 4, AlphaTile},
+			{6, 0, BetaTile}, {6, 2, CenterTile}, {6, 4, AlphaTile},
+		},
+	},
+}
+
+func (d boardDefinition) isHole(row, column int) bool {
+	for _, h := range d.holes {
+		if h.row == row && h.column == column {
+			return true
+		}
+	}
+	return false
+}
+
+// UseBoard changes the board being played to a new definition
+func (g *game) UseBoard(definition boardDefinition) {
+	g.definition = definition
+	g.rows = definition.rows
+	g.cols = definition.cols
+	g.board = newBoard(g.rows, g.cols)
+	g.layout = newLayout(g.layout.width, g.layout.height, g.rows, g.cols)
+	g.PlaceTiles()
+}
+
+// objectiveColumn returns the board column of the objective marker
+func (g game) objectiveColumn() int {
+	return g.definition.markerColumns[g.columnObjective]
+}
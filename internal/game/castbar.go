@@ -0,0 +1,112 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	// seconds of each cast when they are chained as in the encounter
+	CLASSICAL_CONCEPTS_CAST_TIME = 7
+	PANTA_RHEI_CAST_TIME         = 8
+)
+
+// cast is one of the casts the round waits for
+type cast struct {
+	name     message
+	duration float32
+}
+
+// roundCasts returns the casts of a round, Classical Concepts and then Panta
+// Rhei, sharing the round time or, chained, with their own timings
+func (g game) roundCasts() []cast {
+	if g.settings.CastChain {
+		return []cast{
+			{ClassicalConceptsMessage, CLASSICAL_CONCEPTS_CAST_TIME},
+			{PantaRheiMessage, PANTA_RHEI_CAST_TIME},
+		}
+	}
+	return []cast{
+		{ClassicalConceptsMessage, MAX_TIME / 2.0},
+		{PantaRheiMessage, MAX_TIME / 2.0},
+	}
+}
+
+// castsTime returns the seconds all the casts take
+func castsTime(casts []cast) float32 {
+	var total float32
+	for _, c := range casts {
+		total += c.duration
+	}
+	return total
+}
+
+// currentCast returns the cast going on and how many seconds it has left
+func (g game) currentCast() (cast, float32) {
+	remaining := g.timeLeft
+	for i := len(g.casts) - 1; i >= 0; i-- {
+		if remaining <= g.casts[i].duration || i == 0 {
+			return g.casts[i], remaining
+		}
+		remaining -= g.casts[i].duration
+	}
+	return cast{}, 0
+}
+
+// DrawCastBar draws the cast going on as the game does, the bar fills while it
+// casts and turns to the lose color on the last seconds of the round
+func (g game) DrawCastBar(screen *ebiten.Image) {
+	bar := g.layout.bar
+	current, remaining := g.currentCast()
+	progress := float32(1)
+	if current.duration > 0 {
+		progress = 1 - remaining/current.duration
+	}
+
+	barColor := g.palette.timeBar
+	if g.timeLeft <= COUNTDOWN_SECONDS {
+		barColor = g.palette.lose
+	}
+
+	vector.DrawFilledRect(screen, bar.x, bar.y, bar.width, bar.height, gray, false)
+	vector.DrawFilledRect(screen, bar.x, bar.y, bar.width*progress, bar.height, barColor, false)
+	vector.StrokeRect(screen, bar.x, bar.y, bar.width, bar.height, 3, white, false)
+
+	style := defaultTextStyle()
+	style.size = SMALL_FONT_SIZE
+	style.outline = 3
+	style.fit = bar.width / 4
+
+	nameStyle := style
+	nameStyle.align = text.AlignStart
+	nameStyle.fit = bar.width * 3 / 4
+	g.DrawText(screen, g.T(current.name), bar.x+MARGIN, bar.y+bar.height/2, nameStyle)
+
+	style.align = text.AlignEnd
+	g.DrawText(screen, fmt.Sprintf("%.1f", remaining), bar.x+bar.width-MARGIN, bar.y+bar.height/2, style)
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import "github.com/hajimehoshi/ebiten/v2"
+
+// tickSource tells how many ticks there are in a second, so the clock moves
+// the same on every machine no matter how long a frame really took
+type tickSource interface {
+	TPS() int
+}
+
+// ebitenTicks are the ticks of the running game
+type ebitenTicks struct{}
+
+func (ebitenTicks) TPS() int {
+	return ebiten.TPS()
+}
+
+// fixedTicks are ticks for tests and replays that run without ebiten
+type fixedTicks int
+
+func (f fixedTicks) TPS() int {
+	return int(f)
+}
+
+// clock is the game time, it moves once per tick while it is not paused and
+// at the scale it is set to
+type clock struct {
+	source  tickSource
+	now     float64
+	elapsed float64
+	scale   float64
+	paused  bool
+}
+
+func newClock(source tickSource) *clock {
+	return &clock{source: source, scale: 1}
+}
+
+// Tick moves the clock one tick, it needs to be called once per update
+func (c *clock) Tick() {
+	c.elapsed = 0
+	if c.paused {
+		return
+	}
+	c.elapsed = c.scale / float64(c.source.TPS())
+	c.now += c.elapsed
+}
+
+// Now returns the seconds of game time since the clock was created
+func (c *clock) Now() float64 {
+	return c.now
+}
+
+// Elapsed returns the seconds of game time the last tick took
+func (c *clock) Elapsed() float64 {
+	return c.elapsed
+}
+
+func (c *clock) Pause() {
+	c.paused = true
+	c.elapsed = 0
+}
+
+func (c *clock) Resume() {
+	c.paused = false
+}
+
+// SetScale makes the game time go faster, over 1, or slower, under 1
+func (c *clock) SetScale(scale float64) {
+	c.scale = max(0, scale)
+}
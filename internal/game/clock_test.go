@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"math"
+	"testing"
+)
+
+func tickClock(c *clock, ticks int) {
+	for i := 0; i < ticks; i++ {
+		c.Tick()
+	}
+}
+
+func assertSeconds(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("%s = %v, want %v", name, got, want)
+	}
+}
+
+func TestClockTicks(t *testing.T) {
+	c := newClock(fixedTicks(60))
+	tickClock(c, 60)
+	assertSeconds(t, "now", c.Now(), 1)
+	assertSeconds(t, "elapsed", c.Elapsed(), 1.0/60)
+}
+
+func TestClockPauseAndResume(t *testing.T) {
+	c := newClock(fixedTicks(60))
+	tickClock(c, 30)
+
+	c.Pause()
+	assertSeconds(t, "elapsed on pause", c.Elapsed(), 0)
+	tickClock(c, 60)
+	assertSeconds(t, "now while paused", c.Now(), 0.5)
+	assertSeconds(t, "elapsed while paused", c.Elapsed(), 0)
+
+	c.Resume()
+	tickClock(c, 30)
+	assertSeconds(t, "now after resume", c.Now(), 1)
+}
+
+func TestClockScale(t *testing.T) {
+	c := newClock(fixedTicks(60))
+	c.SetScale(2)
+	tickClock(c, 60)
+	assertSeconds(t, "now at double speed", c.Now(), 2)
+
+	c.SetScale(0.5)
+	tickClock(c, 60)
+	assertSeconds(t, "now at half speed", c.Now(), 2.5)
+
+	c.SetScale(-1)
+	tickClock(c, 60)
+	assertSeconds(t, "now with a negative scale", c.Now(), 2.5)
+}
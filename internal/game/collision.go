@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// collision is another player we got in the way of, or that got in ours
+type collision struct {
+	kind   mistake
+	symbol TileState
+	column int
+}
+
+// tileAtUnits returns the tile under a position in tile radius units, if any
+func (g game) tileAtUnits(x, y float32) (bool, BoardPosition) {
+	id, found := g.tileIndex.Find(g.layout.boardX+x*g.layout.tileRadius, g.layout.boardY+y*g.layout.tileRadius)
+	if !found {
+		return false, BoardPosition{}
+	}
+	position := BoardPosition{row: id / g.cols, column: id % g.cols}
+	if g.board[position.row][position.column].state == InvalidTile {
+		return false, BoardPosition{}
+	}
+	return true, position
+}
+
+// findCollisions checks our tile against the rest of the party, for anyone
+// standing in it and for anyone whose spot it is, as we are blocking their
+// tether
+func (g game) findCollisions() []collision {
+	var collisions []collision
+	if !g.playerPicked {
+		return collisions
+	}
+	flipped := g.originalStates.flipped()
+	for _, m := range g.party {
+		if m.lost {
+			continue
+		}
+		if found, tile := g.tileAtUnits(m.x, m.y); found && tile == g.playerPick {
+			collisions = append(collisions, collision{kind: SharedTileMistake, symbol: m.symbol, column: m.column})
+		}
+		res := g.strategy.Resolve(flipped, m.symbol, g.definition.markerColumns[m.column])
+		if res.found && res.spot == g.playerPick {
+			collisions = append(collisions, collision{kind: BlockedTetherMistake, symbol: m.symbol, column: m.column})
+		}
+	}
+	return collisions
+}
+
+// collisionMistake returns why the round is lost because of the collisions,
+// sharing the tile loses even a right pick, blocking a tether only explains
+// a wrong one that has no better reason
+func (g game) collisionMistake() mistake {
+	for _, c := range g.collisions {
+		if c.kind == SharedTileMistake && g.win {
+			return SharedTileMistake
+		}
+	}
+	for _, c := range g.collisions {
+		if c.kind == BlockedTetherMistake && g.mistake == UnknownMistake {
+			return BlockedTetherMistake
+		}
+	}
+	return g.mistake
+}
+
+// collisionsText lists the collisions, one per line, naming the players
+func (g game) collisionsText() string {
+	var lines []string
+	for _, c := range g.collisions {
+		symbol := g.T(AlphaMessage)
+		if c.symbol == BetaTile {
+			symbol = g.T(BetaMessage)
+		}
+		player := symbol + " " + g.waymarks()[c.column].Label
+		switch c.kind {
+		case SharedTileMistake:
+			lines = append(lines, fmt.Sprintf(g.T(SharedTileMessage), player))
+		case BlockedTetherMistake:
+			lines = append(lines, fmt.Sprintf(g.T(BlockedTetherMessage), player))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
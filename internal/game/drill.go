@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// in the drill the round has a phase to mark the spot before Panta Rhei and
+// another to confirm it after the flip
+type drillPhase int
+
+const (
+	NoDrillPhase drillPhase = iota
+	PreFlipPhase
+	FlippingPhase
+	PostFlipPhase
+)
+
+// seconds to confirm the spot after the flip
+const DRILL_CONFIRM_TIME = 5
+
+// mirrored returns the position a tile has after Panta Rhei
+func (g game) mirrored(position BoardPosition) BoardPosition {
+	return BoardPosition{row: g.rows - 1 - position.row, column: g.cols - 1 - position.column}
+}
+
+// MarkPreFlip keeps the spot picked before the flip and starts it
+func (g *game) MarkPreFlip() {
+	g.prePicked, g.prePick = g.FindPlayerPosition()
+	g.RemoveTileWithState(PlayerTile)
+	g.RemoveTileWithState(MouseOverTile)
+	g.Flip()
+	g.drillPhase = FlippingPhase
+	g.StartFlip()
+}
+
+func (g *game) UpdateDrillFlip() {
+	if g.flipDone() {
+		g.drillPhase = PostFlipPhase
+		g.casts = []cast{{ConfirmCastMessage, DRILL_CONFIRM_TIME}}
+		g.timeLeft = DRILL_CONFIRM_TIME
+	}
+}
+
+func (g game) preFlipCorrect() bool {
+	return g.prePicked && g.resolution.found && g.prePick == g.mirrored(g.resolution.spot)
+}
+
+// drillScore gives half of the score to each phase
+func drillScore(preFlipCorrect, win bool, hintsUsed int) int {
+	score := 0
+	if preFlipCorrect {
+		score += MAX_SCORE / 2
+	}
+	if win {
+		score += MAX_SCORE / 2
+	}
+	return max(0, score-hintsUsed*HINT_PENALTY)
+}
+
+func (g game) DrawDrillPhase(screen *ebiten.Image) {
+	var instruction message
+	switch g.drillPhase {
+	case PreFlipPhase:
+		instruction = DrillPreFlipMessage
+	case PostFlipPhase:
+		instruction = DrillPostFlipMessage
+	default:
+		return
+	}
+
+	area := g.layout.caption
+	style := defaultTextStyle()
+	style.size = CAPTION_FONT_SIZE
+	style.wrap = area.width
+	g.DrawText(screen, g.T(instruction), g.layout.statusX, g.layout.statusY, style)
+}
+
+func (g game) drillResult() string {
+	result := func(correct bool) string {
+		if correct {
+			return g.T(RightMessage)
+		}
+		return g.T(WrongMessage)
+	}
+	return fmt.Sprintf(g.T(DrillResultMessage), result(g.preFlipCorrect()), result(g.win))
+}
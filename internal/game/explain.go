@@ -0,0 +1,222 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/juan-medina/classical-concepts-2-trainer/internal/shapes"
+)
+
+type explanationStep int
+
+const (
+	OriginalBoardStep explanationStep = iota
+	FlipStep
+	HexagonStep
+	SymbolStep
+	SpotStep
+)
+
+const (
+	EXPLANATION_STEPS = 5
+	// seconds that the animation of each step takes
+	EXPLANATION_STEP_TIME = 1
+)
+
+var highlightColor = color.RGBA64{0x2222, 0x2222, 0x2222, 0x2222}
+
+func (g *game) OpenExplanation() {
+	g.explainStep = OriginalBoardStep
+	g.StartExplainStep()
+	g.state = ExplainState
+}
+
+func (g *game) CloseExplanation() {
+	g.state = EndState
+}
+
+func (g *game) NextStep() {
+	if g.explainStep < EXPLANATION_STEPS-1 {
+		g.explainStep++
+		g.StartExplainStep()
+	}
+}
+
+func (g *game) PreviousStep() {
+	if g.explainStep > 0 {
+		g.explainStep--
+		g.StartExplainStep()
+	}
+}
+
+func (g *game) UpdateExplanation() {
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight):
+		g.NextStep()
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft):
+		g.PreviousStep()
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		g.CloseExplanation()
+	}
+}
+
+func (g *game) StartExplainStep() {
+	g.timeline.Start(ExplainAnimation, newTween(0, 1, g.clock.Now(), EXPLANATION_STEP_TIME, linear))
+}
+
+// explanationProgress goes from 0 to 1 while the current step is animated
+func (g game) explanationProgress() float32 {
+	return g.timeline.Value(ExplainAnimation, g.clock.Now())
+}
+
+// DrawFlip draws the board while Panta Rhei moves the symbols, progress goes
+// from 0, before the flip, to 1, when it is done
+func (g game) DrawFlip(screen *ebiten.Image, states boardStates, progress float32) {
+	for r := 0; r < g.rows; r++ {
+		for c := 0; c < g.cols; c++ {
+			if !isSymbol(states[r][c]) {
+				g.DrawTile(g.batch, g.board[r][c].x, g.board[r][c].y, 0, 1, states[r][c])
+			}
+		}
+	}
+
+	for r := 0; r < g.rows; r++ {
+		for c := 0; c < g.cols; c++ {
+			if isSymbol(states[r][c]) {
+				from := g.board[r][c]
+				to := g.board[g.rows-1-r][g.cols-1-c]
+				x := from.x + (to.x-from.x)*progress
+				y := from.y + (to.y-from.y)*progress
+				g.DrawTile(g.batch, x, y, 0, 1, states[r][c])
+			}
+		}
+	}
+	g.batch.Flush(screen)
+}
+
+func (g game) DrawRing(screen *ebiten.Image, position BoardPosition, clr color.Color) {
+	t := g.board[position.row][position.column]
+	pulse := float32(math.Sin(float64(g.ticks)/10)) * 0.1
+	// drawn at its widest and scaled down while it pulses
+	tileRadius := g.layout.tileRadius
+	radius := tileRadius * 1.7
+	key := spriteKey{kind: RingSprite, size: radius, stroke: rgba64(clr)}
+	drawSprite(screen, g.sprites.Get(key, radius+tileRadius/4+tileRadius/20+2, func(img *ebiten.Image, x, y float32) {
+		shapes.Circle(x, y, radius).Draw(img, shapes.Style{
+			Stroke:      clr,
+			StrokeWidth: tileRadius / 10,
+			Glow:        clr,
+			GlowWidth:   tileRadius / 4,
+			AntiAlias:   true,
+		})
+	}), t.x, t.y, (1.6+pulse)/1.7, 0)
+}
+
+func (g game) DrawColumnHighlight(screen *ebiten.Image, column int, alpha float32) {
+	x, y := g.layout.tilePosition(0, column)
+	width := g.layout.stepX * 0.9
+	height := float32(g.rows-1)*g.layout.stepY + g.layout.tileRadius*TILE_FOOTPRINT
+	clr := color.RGBA64{
+		uint16(float32(highlightColor.R) * alpha),
+		uint16(float32(highlightColor.G) * alpha),
+		uint16(float32(highlightColor.B) * alpha),
+		uint16(float32(highlightColor.A) * alpha),
+	}
+	vector.DrawFilledRect(screen, x-width/2, y-g.layout.tileRadius*TILE_FOOTPRINT/2, width, height, clr, false)
+}
+
+func (g game) DrawExplanation(screen *ebiten.Image) {
+	progress := g.explanationProgress()
+	res := g.resolution
+
+	switch g.explainStep {
+	case OriginalBoardStep:
+		g.DrawStates(screen, g.originalStates)
+	case FlipStep:
+		g.DrawFlip(screen, g.originalStates, progress)
+	default:
+		g.DrawStates(screen, g.originalStates.flipped())
+	}
+
+	if g.explainStep >= HexagonStep {
+		alpha := float32(1)
+		if g.explainStep == HexagonStep {
+			alpha = progress
+		}
+		g.DrawColumnHighlight(screen, res.center.column, alpha)
+	}
+
+	if res.found {
+		if g.explainStep >= HexagonStep {
+			g.DrawRing(screen, res.center, g.palette.center)
+		}
+		if g.explainStep >= SymbolStep {
+			symbolColor := g.palette.beta
+			if g.symbolObjective == AlphaTile {
+				symbolColor = g.palette.alpha
+			}
+			g.DrawRing(screen, res.symbol, symbolColor)
+		}
+		if g.explainStep == SpotStep {
+			center := g.board[res.center.row][res.center.column]
+			symbol := g.board[res.symbol.row][res.symbol.column]
+			toX := center.x + (symbol.x-center.x)*progress
+			toY := center.y + (symbol.y-center.y)*progress
+			vector.StrokeLine(screen, center.x, center.y, toX, toY, g.layout.tileRadius/12, g.palette.tether, true)
+			g.DrawRing(screen, res.spot, white)
+		}
+	}
+
+	var caption string
+	switch g.explainStep {
+	case OriginalBoardStep:
+		caption = g.T(ExplainOriginalMessage)
+	case FlipStep:
+		caption = g.T(ExplainFlipMessage)
+	case HexagonStep:
+		caption = fmt.Sprintf(g.T(ExplainHexagonMessage), g.waymarks()[g.columnObjective].Label)
+	case SymbolStep:
+		symbol := g.T(BetaMessage)
+		if g.symbolObjective == AlphaTile {
+			symbol = g.T(AlphaMessage)
+		}
+		caption = fmt.Sprintf(g.T(ExplainSymbolMessage), symbol)
+	case SpotStep:
+		caption = g.T(ExplainSpotMessage)
+	}
+
+	area := g.layout.caption
+	style := defaultTextStyle()
+	style.size = SMALL_FONT_SIZE
+	style.verticalAlign = text.AlignStart
+	style.wrap = area.width
+	g.DrawText(screen, fmt.Sprintf("%d/%d\n%s", g.explainStep+1, EXPLANATION_STEPS, caption), area.x+area.width/2, area.y, style)
+}
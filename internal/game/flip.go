@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+const (
+	// seconds Panta Rhei takes by default, and the step and limit to change it
+	FLIP_TIME      = 1
+	FLIP_TIME_STEP = 0.5
+	MAX_FLIP_TIME  = 3
+)
+
+// StartFlip starts Panta Rhei moving the symbols, it eases in and out so they
+// start and stop smoothly
+func (g *game) StartFlip() {
+	g.timeline.Start(FlipAnimation, newTween(0, 1, g.clock.Now(), g.settings.FlipTime, easeInOutCubic))
+}
+
+// flipProgress returns how far the flip is, from 0 to 1
+func (g game) flipProgress() float32 {
+	return g.timeline.Value(FlipAnimation, g.clock.Now())
+}
+
+func (g game) flipDone() bool {
+	return g.timeline.Tween(FlipAnimation).done(g.clock.Now())
+}
+
+// FlipAndReveal flips the board and grows the tether right after it
+func (g *game) FlipAndReveal() {
+	g.StartFlip()
+	g.timeline.After(TetherAnimation, FlipAnimation, 0, newTween(0, 1, 0, TETHER_TIME, easeInOutCubic))
+}
+
+// EndFlipping tells if the end screen is still showing Panta Rhei
+func (g game) EndFlipping() bool {
+	return g.endFlipping
+}
+
+func (g *game) UpdateEndFlip() {
+	if g.endFlipping && g.flipDone() {
+		g.endFlipping = false
+		g.PlayResultSound()
+	}
+}
+
+// RevealResult plays the result sound and grows the tether, for a board that
+// is already flipped
+func (g *game) RevealResult() {
+	g.PlayResultSound()
+	g.timeline.Start(TetherAnimation, newTween(0, 1, g.clock.Now(), TETHER_TIME, easeInOutCubic))
+}
+
+func (g *game) PlayResultSound() {
+	if g.win {
+		g.PlaySound(SuccessSound)
+	} else {
+		g.PlaySound(FailureSound)
+	}
+}
@@ -24,20 +24,17 @@ package game
 
 import (
 	"embed"
-	"image"
+	"fmt"
 	"image/color"
-	"io/fs"
 	"math"
 	"math/rand"
-	"time"
 
-	"github.com/golang/freetype/truetype"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 
 	"github.com/juan-medina/classical-concepts-2-trainer/internal/shapes"
-	"golang.org/x/image/font"
-	"golang.org/x/image/math/fixed"
 )
 
 var (
@@ -49,6 +46,7 @@ var (
 	darkGreen  = color.RGBA64{0x0000, 0x8888, 0x0000, 0xFFFF}
 	green      = color.RGBA64{0x0000, 0xFFFF, 0x0000, 0xFFFF}
 	white      = color.RGBA64{0xFFFF, 0xFFFF, 0xFFFF, 0xFFFF}
+	black      = color.RGBA64{0x0000, 0x0000, 0x0000, 0xFFFF}
 	gray       = color.RGBA64{0x1111, 0x1111, 0x1111, 0xFFFF}
 	lightGray  = color.RGBA64{0x8888, 0x8888, 0x8888, 0xFFFF}
 )
@@ -58,10 +56,7 @@ const (
 	HEIGHT        = 1080
 	BUTTON_WIDTH  = 300
 	BUTTON_HEIGHT = 100
-	NUM_ROWS      = 5
-	NUM_COLS      = 7
-	TITLE_RADIUS  = 60
-	BAR_WIDTH     = 1400
+	NUM_MARKERS   = 4
 	MAX_TIME      = 15
 )
 
@@ -83,6 +78,10 @@ const (
 	StandByState GameState = iota
 	PlayingState
 	EndState
+	SettingsState
+	ExplainState
+	StatsState
+	PausedState
 )
 
 type tile struct {
@@ -92,84 +91,123 @@ type tile struct {
 	rotation float32
 }
 
+type button struct {
+	label  message
+	action func()
+}
+
 type BoardPosition struct {
 	row, column int
 }
 
 type game struct {
-	rows                    int
-	cols                    int
-	board                   [NUM_ROWS][NUM_COLS]tile
-	defaultFont             font.Face
-	smallFont               font.Face
-	aText                   *ebiten.Image
-	bText                   *ebiten.Image
-	cText                   *ebiten.Image
-	dText                   *ebiten.Image
-	alphaObjetiveText       *ebiten.Image
-	betaObjetiveText        *ebiten.Image
-	state                   GameState
-	buttonX                 float32
-	buttonY                 float32
-	buttonOver              bool
-	buttonColor             color.Color
-	buttonText              *ebiten.Image
-	timeLeft                float32
-	lastUpdateTime          time.Time
-	objectiveX              float32
-	objectiveY              float32
-	symbolObjective         TileState
-	columnObjective         int
-	centerSymbolPosition    BoardPosition
-	objectiveSymbolPosition BoardPosition
-	win                     bool
-	winningText             *ebiten.Image
-	loosingText             *ebiten.Image
-}
-
-func (g game) ShapeHit(shapeX, shapeY float32, pointX, pointY float32) bool {
-	var adjustRadius float32 = TITLE_RADIUS * 1.25
-	if pointX > shapeX-adjustRadius && pointX < shapeX+adjustRadius && pointY > shapeY-adjustRadius && pointY < shapeY+adjustRadius {
-		return true
-	}
-	return false
-}
-
-func (g game) ButtonHit(x, y float32) bool {
-	if x > g.buttonX && x < g.buttonX+BUTTON_WIDTH && y > g.buttonY && y < g.buttonY+BUTTON_HEIGHT {
-		return true
-	}
-	return false
+	rows            int
+	cols            int
+	board           board
+	definition      boardDefinition
+	layout          layout
+	batch           *shapes.Batch
+	sprites         *sprites
+	tileIndex       *shapes.Index
+	timeline        *timeline
+	character       character
+	gamepads        []ebiten.GamepadID
+	party           []partyMember
+	collisions      []collision
+	casts           []cast
+	strategy        strategy
+	lastState       GameState
+	fonts           *fonts
+	state           GameState
+	buttonOver      int
+	settings        settings
+	palette         palette
+	menuReturnState GameState
+	menuSelected    int
+	// the waymark the settings edit
+	waymarkEdit int
+	language    string
+	sounds      *sounds
+	timeLeft    float32
+	clock       *clock
+	// the menus keep moving while the game clock is paused or scaled
+	uiClock         *clock
+	symbolObjective TileState
+	columnObjective int
+	playerPicked    bool
+	playerPick      BoardPosition
+	win             bool
+	originalStates  boardStates
+	resolution      resolution
+	explainStep     explanationStep
+	mistake         mistake
+	stats           stats
+	ticks           int
+	hintsUsed       int
+	score           int
+	boardFlipped    bool
+	drillPhase      drillPhase
+	// the mode the round is played in, the settings may change after it
+	roundMode   gameMode
+	prePicked   bool
+	prePick     BoardPosition
+	endFlipping bool
+}
+
+// Buttons returns the buttons shown in the current state
+func (g *game) Buttons() []button {
+	switch g.state {
+	case StandByState:
+		return []button{{TryMessage, g.Reset}, {SettingsMessage, g.OpenSettings}, {StatsMessage, g.OpenStats}}
+	case EndState:
+		return []button{{TryMessage, g.Reset}, {SettingsMessage, g.OpenSettings}, {ExplainMessage, g.OpenExplanation}, {StatsMessage, g.OpenStats}}
+	case SettingsState:
+		return []button{{BackMessage, g.CloseSettings}}
+	case StatsState:
+		return []button{{BackMessage, g.CloseStats}}
+	case PausedState:
+		return []button{{ResumeMessage, g.Resume}, {RestartMessage, g.Restart}, {QuitMessage, g.Quit}}
+	case ExplainState:
+		return []button{{NextMessage, g.NextStep}, {PreviousMessage, g.PreviousStep}, {CloseMessage, g.CloseExplanation}}
+	}
+	return nil
 }
 
 func (g *game) UpdateButtons() {
 	ebiten.SetCursorShape(ebiten.CursorShapeDefault)
 	x, y := ebiten.CursorPosition()
-	if g.ButtonHit(float32(x), float32(y)) {
-		g.buttonColor = green
-		ebiten.SetCursorShape(ebiten.CursorShapePointer)
-		if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-			g.Reset()
+
+	g.buttonOver = -1
+	for i, b := range g.Buttons() {
+		if g.layout.buttonRect(i).hit(float32(x), float32(y)) {
+			g.buttonOver = i
+			ebiten.SetCursorShape(ebiten.CursorShapePointer)
+			if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+				b.action()
+			}
+			return
 		}
-	} else {
-		g.buttonColor = darkGreen
 	}
 }
 
 func (g *game) UpdateTimeBar() {
-	// Calculate time elapsed since last update
-	elapsedTime := time.Since(g.lastUpdateTime)
-	g.lastUpdateTime = time.Now()
-
-	// Convert elapsed time to milliseconds
-	elapsedMillis := elapsedTime.Milliseconds()
+	// Subtract the game time of this tick from time left
+	previousSecond := int(math.Ceil(float64(g.timeLeft)))
+	g.timeLeft -= float32(g.clock.Elapsed())
 
-	// Subtract elapsed time from time left
-	g.timeLeft -= float32(elapsedMillis) / 1000 // convert milliseconds to seconds
+	// count down the last seconds with a sound
+	second := int(math.Ceil(float64(g.timeLeft)))
+	if second != previousSecond && second > 0 && second <= COUNTDOWN_SECONDS {
+		g.PlaySound(CountdownSound)
+	}
 
 	if g.timeLeft <= 0 {
 		g.timeLeft = 0
-		g.End()
+		if g.drillPhase == PreFlipPhase {
+			g.MarkPreFlip()
+		} else {
+			g.End()
+		}
 	}
 }
 
@@ -190,97 +228,198 @@ func (g *game) HandleMouseInBoard() {
 	cy := float32(y)
 
 	ebiten.SetCursorShape(ebiten.CursorShapeDefault)
-	for r := 0; r < g.rows; r++ {
-		for c := 0; c < g.cols; c++ {
-			if g.board[r][c].state == EmptyTile || g.board[r][c].state == MouseOverTile {
-				if g.ShapeHit(g.board[r][c].x, g.board[r][c].y, cx, cy) {
-					ebiten.SetCursorShape(ebiten.CursorShapePointer)
-					if ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft) {
-						g.SetTile(c, r, PlayerTile)
-						return
-					} else {
-						g.SetTile(c, r, MouseOverTile)
-						return
-					}
-				}
+	id, found := g.tileIndex.Find(cx, cy)
+	if !found {
+		return
+	}
+	r, c := id/g.cols, id%g.cols
+	if g.board[r][c].state == EmptyTile || g.board[r][c].state == MouseOverTile {
+		ebiten.SetCursorShape(ebiten.CursorShapePointer)
+		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			g.SetTile(c, r, PlayerTile)
+			g.PlaySound(SelectSound)
+			if g.drillPhase == PreFlipPhase {
+				g.MarkPreFlip()
 			}
+		} else {
+			g.SetTile(c, r, MouseOverTile)
 		}
 	}
 }
 
 func (g *game) Update() error {
+	g.ticks++
+	g.clock.Tick()
+	g.uiClock.Tick()
+
+	if inpututil.IsKeyJustPressed(ebiten.KeyM) {
+		g.settings.Mute = !g.settings.Mute
+		g.settings.save()
+	}
+
 	switch g.state {
 	case StandByState:
 		g.UpdateButtons()
 	case PlayingState:
+		if g.UpdatePauseRequest() {
+			break
+		}
+		if g.drillPhase == FlippingPhase {
+			g.UpdateDrillFlip()
+			break
+		}
 		g.UpdateTimeBar()
 		g.UpdateBoard()
-		g.HandleMouseInBoard()
+		g.UpdateHints()
+		g.UpdateParty()
+		if g.roundMode == MovementMode {
+			g.UpdateCharacter()
+		} else {
+			g.HandleMouseInBoard()
+		}
 	case EndState:
+		if g.EndFlipping() {
+			g.UpdateEndFlip()
+			break
+		}
+		g.UpdateButtons()
+	case SettingsState:
+		g.UpdateButtons()
+		g.UpdateSettings()
+	case ExplainState:
+		g.UpdateButtons()
+		g.UpdateExplanation()
+	case StatsState:
 		g.UpdateButtons()
+		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+			g.CloseStats()
+		}
+	case PausedState:
+		g.UpdateButtons()
+		g.UpdatePause()
+	}
+
+	if g.state != g.lastState {
+		g.lastState = g.state
+		g.timeline.Start(StateAnimation, newTween(0, 1, g.uiClock.Now(), TRANSITION_TIME, easeOutQuad))
 	}
 	return nil
 }
 
-func (g game) DrawButtons(screen *ebiten.Image) {
-	vector.DrawFilledRect(screen, g.buttonX, g.buttonY, BUTTON_WIDTH, BUTTON_HEIGHT, g.buttonColor, false)
-
-	op := &ebiten.DrawImageOptions{}
-	op.ColorScale.Scale(1, 1, 1, 0.5)
+func (g game) DrawButton(screen *ebiten.Image, b button, area rect, over bool) {
+	buttonColor := darkGreen
+	if over {
+		buttonColor = green
+	}
+	vector.DrawFilledRect(screen, area.x, area.y, area.width, area.height, buttonColor, false)
 
-	op.GeoM.Translate(float64(g.buttonX)+70, float64(g.buttonY)-10)
-	screen.DrawImage(g.buttonText, op)
+	style := defaultTextStyle()
+	style.color = color.RGBA64{0x7FFF, 0x7FFF, 0x7FFF, 0x7FFF}
+	style.fit = area.width - MARGIN
+	g.DrawText(screen, g.T(b.label), area.x+area.width/2, area.y+area.height/2, style)
+}
 
+func (g game) DrawButtons(screen *ebiten.Image) {
+	// buttons slide in, one after another, when the state changes
+	transition := g.timeline.Tween(StateAnimation)
+	for i, b := range g.Buttons() {
+		area := g.layout.buttonRect(i)
+		offset := 1 - transition.delayed(BUTTON_STAGGER*float64(i)).value(g.uiClock.Now())
+		if g.layout.portrait {
+			area.y += offset * (g.layout.height - area.y)
+		} else {
+			area.x += offset * (g.layout.width - area.x)
+		}
+		g.DrawButton(screen, b, area, i == g.buttonOver)
+	}
 }
 
 func (g game) DrawMarkers(screen *ebiten.Image) {
-	op := &ebiten.DrawImageOptions{}
-
-	op.GeoM.Translate(150, 0)
-	screen.DrawImage(g.aText, op)
+	for i, column := range g.definition.markerColumns {
+		g.DrawWaymark(screen, g.layout.markerX(column), g.layout.markersY, g.layout.tileRadius*WAYMARK_SIZE, i)
+	}
+}
 
-	op.GeoM.Translate(360, 0)
-	screen.DrawImage(g.bText, op)
+// DrawSymbol queues an alpha, beta or center shape in a batch, in shape only
+// mode it gets an outline and a center dot so it does not rely on its color
+func (g game) DrawSymbol(batch *shapes.Batch, x, y, radius, rotation float32, state TileState) {
+	var sides int
+	var symbolColor color.Color
+	switch state {
+	case AlphaTile:
+		sides, rotation, symbolColor = 3, rotation-90, g.palette.alpha
+	case BetaTile:
+		sides, rotation, symbolColor = 4, rotation-45, g.palette.beta
+	case CenterTile:
+		sides, symbolColor = 6, g.palette.center
+	default:
+		return
+	}
 
-	op.GeoM.Translate(360, 0)
-	screen.DrawImage(g.cText, op)
+	if g.settings.ShapeOnly {
+		batch.AddPolygon(x, y, radius*1.25, sides, rotation, white)
+		batch.AddPolygon(x, y, radius*1.1, sides, rotation, black)
+	}
+	batch.AddPolygon(x, y, radius, sides, rotation, symbolColor)
+	if g.settings.ShapeOnly {
+		batch.AddPolygon(x, y, radius/5, sides, rotation, black)
+	}
+}
 
-	op.GeoM.Translate(360, 0)
-	screen.DrawImage(g.dText, op)
+// DrawTile queues a tile in a batch, it is drawn when the batch is flushed
+func (g game) DrawTile(batch *shapes.Batch, x, y, rotation, scale float32, state TileState) {
+	radius := g.layout.tileRadius * scale
+	switch state {
+	case AlphaTile, BetaTile, CenterTile:
+		g.DrawSymbol(batch, x, y, radius, rotation, state)
+	case MouseOverTile:
+		batch.AddPolygon(x, y, radius*1.5, 4, rotation-45, lightGray)
+	case PlayerTile:
+		batch.AddPolygon(x, y, radius*1.5, 4, rotation-45, white)
+	case EmptyTile:
+		batch.AddPolygon(x, y, radius*1.5, 4, rotation-45, gray)
+	}
 }
 
 func (g game) DrawBoard(screen *ebiten.Image) {
+	g.DrawStates(screen, g.States())
+}
+
+// DrawStates draws the board tiles with the given states
+func (g game) DrawStates(screen *ebiten.Image, states boardStates) {
 	for r := 0; r < g.rows; r++ {
 		for c := 0; c < g.cols; c++ {
-			switch g.board[r][c].state {
-			case AlphaTile:
-				shapes.DrawPolygon(screen, g.board[r][c].x, g.board[r][c].y, TITLE_RADIUS, 3, g.board[r][c].rotation-90, red)
-			case BetaTile:
-				shapes.DrawPolygon(screen, g.board[r][c].x, g.board[r][c].y, TITLE_RADIUS, 4, g.board[r][c].rotation-45, yellow)
-			case CenterTile:
-				shapes.DrawPolygon(screen, g.board[r][c].x, g.board[r][c].y, TITLE_RADIUS, 6, g.board[r][c].rotation, blue)
-			case MouseOverTile:
-				shapes.DrawPolygon(screen, g.board[r][c].x, g.board[r][c].y, TITLE_RADIUS*1.5, 4, g.board[r][c].rotation-45, lightGray)
-			case PlayerTile:
-				shapes.DrawPolygon(screen, g.board[r][c].x, g.board[r][c].y, TITLE_RADIUS*1.5, 4, g.board[r][c].rotation-45, white)
-			case EmptyTile:
-				shapes.DrawPolygon(screen, g.board[r][c].x, g.board[r][c].y, TITLE_RADIUS*1.5, 4, g.board[r][c].rotation-45, gray)
-			}
+			g.DrawTile(g.batch, g.board[r][c].x, g.board[r][c].y, g.board[r][c].rotation, g.tileScale(r, c, states[r][c]), states[r][c])
 		}
 	}
+	g.batch.Flush(screen)
 }
 
-func (g game) DrawTimeBar(screen *ebiten.Image) {
-	redLength := float32(g.timeLeft) / float32(MAX_TIME) * BAR_WIDTH
-	vector.DrawFilledRect(screen, 40, HEIGHT-200, redLength, 100, red, false)
-	vector.StrokeRect(screen, 40, HEIGHT-200, BAR_WIDTH, 100, 3, white, false)
+// tileScale returns the size of a tile while it spawns, one after another, and
+// while the mouse is over it
+func (g game) tileScale(row, column int, state TileState) float32 {
+	now := g.clock.Now()
+	scale := g.timeline.Tween(SpawnAnimation).delayed(SPAWN_STAGGER * float64(row*g.cols+column)).value(now)
+	if state == MouseOverTile {
+		scale *= g.timeline.Value(HoverAnimation, now)
+	}
+	return scale
 }
 
 func (g game) DrawTether(screen *ebiten.Image) {
-	center := g.board[g.centerSymbolPosition.row][g.centerSymbolPosition.column]
-	objective := g.board[g.objectiveSymbolPosition.row][g.objectiveSymbolPosition.column]
+	if !g.resolution.found {
+		return
+	}
+	center := g.board[g.resolution.center.row][g.resolution.center.column]
+	objective := g.board[g.resolution.symbol.row][g.resolution.symbol.column]
+
+	// the tether grows from the hexagon to the symbol
+	grow := g.timeline.Value(TetherAnimation, g.clock.Now())
+	objective.x = center.x + (objective.x-center.x)*grow
+	objective.y = center.y + (objective.y-center.y)*grow
 
 	var fromX, fromY, width, height float32
+	thickness := g.layout.tileRadius / 12
 
 	// if is horizontal draw a horizontal line using a rect
 	if objective.y == center.y {
@@ -292,7 +431,8 @@ func (g game) DrawTether(screen *ebiten.Image) {
 			fromY = center.y
 		}
 		width = float32(math.Abs(float64(objective.x - center.x)))
-		height = 5
+		height = thickness
+		fromY -= thickness / 2
 	} else {
 		if objective.y < center.y {
 			fromX = objective.x
@@ -301,50 +441,73 @@ func (g game) DrawTether(screen *ebiten.Image) {
 			fromX = center.x
 			fromY = center.y
 		}
-		width = 5
+		width = thickness
+		fromX -= thickness / 2
 		height = float32(math.Abs(float64(objective.y - center.y)))
 	}
 
-	vector.DrawFilledRect(screen, fromX, fromY, width, height, darkPurple, false)
+	vector.DrawFilledRect(screen, fromX, fromY, width, height, g.palette.tether, false)
 }
 
 func (g game) DrawObjective(screen *ebiten.Image) {
-	op := &ebiten.DrawImageOptions{}
-
-	op.GeoM.Translate(float64(g.objectiveX), float64(g.objectiveY))
+	style := defaultTextStyle()
+	style.color = g.columnColor(g.columnObjective)
+	style.fit = g.layout.panel.width - MARGIN*2
 
-	var objectiveColor color.RGBA64
-	switch g.columnObjective {
-	case 0:
-		objectiveColor = red
-	case 1:
-		objectiveColor = green
-	case 2:
-		objectiveColor = blue
-	case 3:
-		objectiveColor = purple
+	switch g.settings.Assignment {
+	case QuizAssignment:
+		g.DrawAssignment(screen, g.layout.objectiveY)
+		return
+	case IconsAssignment:
+		g.DrawAssignment(screen, g.layout.objectiveY+g.layout.tileRadius*1.5)
 	}
 
-	rc, gc, bc, ac := objectiveColor.RGBA()
-
-	op.ColorScale.Scale(float32(rc)/float32(255), float32(gc)/float32(255), float32(bc)/float32(255), float32(ac)/float32(255))
-
+	objectiveText := g.T(BetaMessage)
 	if g.symbolObjective == AlphaTile {
-		screen.DrawImage(g.alphaObjetiveText, op)
-	} else {
-		screen.DrawImage(g.betaObjetiveText, op)
+		objectiveText = g.T(AlphaMessage)
+	}
+	g.DrawText(screen, objectiveText, g.layout.objectiveX, g.layout.objectiveY, style)
+
+	// in shape only mode the column is told by its marker and the symbol by its shape
+	if g.settings.ShapeOnly && g.settings.Assignment == TextAssignment {
+		offset := g.layout.tileRadius
+		y := g.layout.objectiveY + offset*1.5
+		g.DrawSymbol(g.batch, g.layout.objectiveX-offset, y, offset/2, 0, g.symbolObjective)
+		g.batch.Flush(screen)
+		g.DrawWaymark(screen, g.layout.objectiveX+offset, y, offset/2, g.columnObjective)
 	}
 
 }
 
 func (g game) DrawWinningStatus(screen *ebiten.Image) {
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(500, 900)
+	style := defaultTextStyle()
+	style.wrap = g.layout.panel.width - MARGIN*2
+	style.outline = 3
+	statusText := g.T(LoseMessage)
+	style.color = g.palette.lose
 	if g.win {
-		screen.DrawImage(g.winningText, op)
-	} else {
-		screen.DrawImage(g.loosingText, op)
+		statusText = g.T(WinMessage)
+		style.color = g.palette.win
+	}
+	g.DrawText(screen, statusText, g.layout.statusX, g.layout.statusY, style)
+
+	_, height := g.MeasureText(statusText, style)
+	area := g.layout.caption
+	detailStyle := defaultTextStyle()
+	detailStyle.size = CAPTION_FONT_SIZE
+	detailStyle.verticalAlign = text.AlignStart
+	detailStyle.wrap = area.width
+	detail := fmt.Sprintf(g.T(ScoreMessage), g.score)
+	if !g.win {
+		detail = g.T(mistakeMessages[g.mistake])
+	}
+	if g.drillPhase != NoDrillPhase {
+		detail = g.drillResult() + "\n" + detail
+	}
+	if len(g.collisions) > 0 {
+		detail += "\n" + g.collisionsText()
 	}
+	g.DrawText(screen, detail, g.layout.statusX, g.layout.statusY+height/2+MARGIN, detailStyle)
 }
 
 func (g game) Draw(screen *ebiten.Image) {
@@ -353,61 +516,92 @@ func (g game) Draw(screen *ebiten.Image) {
 		g.DrawButtons(screen)
 		g.DrawMarkers(screen)
 	case PlayingState:
-		g.DrawBoard(screen)
-		g.DrawTimeBar(screen)
+		if g.drillPhase == FlippingPhase {
+			g.DrawFlip(screen, g.originalStates, g.flipProgress())
+		} else {
+			g.DrawBoard(screen)
+			g.DrawHints(screen)
+		}
+		g.DrawDrillPhase(screen)
+		g.DrawParty(screen)
+		if g.roundMode == MovementMode {
+			g.DrawCharacter(screen)
+			g.DrawMovementHelp(screen)
+		}
+		g.DrawCastBar(screen)
 		g.DrawMarkers(screen)
 		g.DrawObjective(screen)
 	case EndState:
+		if g.EndFlipping() {
+			g.DrawFlip(screen, g.originalStates, g.flipProgress())
+			g.DrawMarkers(screen)
+			g.DrawObjective(screen)
+			break
+		}
 		g.DrawButtons(screen)
 		g.DrawBoard(screen)
 		g.DrawMarkers(screen)
 		g.DrawObjective(screen)
 		g.DrawTether(screen)
+		g.DrawParty(screen)
+		if g.roundMode == MovementMode {
+			g.DrawCharacter(screen)
+		}
+		g.DrawPickResult(screen)
 		g.DrawWinningStatus(screen)
+	case SettingsState:
+		g.DrawButtons(screen)
+		g.DrawSettings(screen)
+	case ExplainState:
+		g.DrawButtons(screen)
+		g.DrawMarkers(screen)
+		g.DrawObjective(screen)
+		g.DrawExplanation(screen)
+	case StatsState:
+		g.DrawButtons(screen)
+		g.DrawStats(screen)
+	case PausedState:
+		g.DrawButtons(screen)
+		g.DrawMarkers(screen)
+		g.DrawObjective(screen)
+		g.DrawCastBar(screen)
+		g.DrawPause(screen)
 	}
 }
 
-func (g game) CreateTextImage(text string, color color.Color, face font.Face) *ebiten.Image {
-	textImage := image.NewRGBA(g.getTextDimensions(text))
+func (g *game) Layout(outsideWidth, outsideHeight int) (int, int) {
+	scale := ebiten.Monitor().DeviceScaleFactor()
+	width := int(float64(outsideWidth) * scale)
+	height := int(float64(outsideHeight) * scale)
 
-	// Draw the text on the image
-	drawer := &font.Drawer{
-		Dst:  textImage,
-		Src:  image.NewUniform(color),
-		Face: face,
-		Dot:  fixed.P(0, int(face.Metrics().Height.Ceil())),
+	if float32(width) != g.layout.width || float32(height) != g.layout.height {
+		g.layout = newLayout(float32(width), float32(height), g.rows, g.cols)
+		g.PlaceTiles()
+		g.sprites.Clear()
 	}
-	drawer.DrawString(text)
 
-	// Convert *image.RGBA to *ebiten.Image
-	return ebiten.NewImageFromImage(textImage)
+	return width, height
 }
 
-func (g game) getTextDimensions(text string) image.Rectangle {
-	width := 0
-	maxHeight := 0
-	minHeight := 0
-
-	for _, ch := range text {
-		b, a, ok := g.defaultFont.GlyphBounds(ch)
-		if !ok {
-			continue
-		}
-		if int(b.Max.Y) > maxHeight {
-			maxHeight = int(b.Max.Y)
-		}
-		if int(b.Min.Y) < minHeight {
-			minHeight = int(b.Min.Y)
+// PlaceTiles puts the tiles in their screen positions and indexes their
+// shapes, by row and column, to find which one is under the mouse, holes are
+// not drawn so they are left out
+func (g *game) PlaceTiles() {
+	g.tileIndex = shapes.NewIndex(g.layout.stepX)
+	for r := 0; r < g.rows; r++ {
+		for c := 0; c < g.cols; c++ {
+			t := &g.board[r][c]
+			t.x, t.y = g.layout.tilePosition(r, c)
+			if !g.definition.isHole(r, c) {
+				g.tileIndex.Add(r*g.cols+c, g.tileShape(t.x, t.y, t.rotation))
+			}
 		}
-		width += a.Ceil()
 	}
-
-	height := maxHeight - minHeight
-	return image.Rect(0, 0, width, height)
 }
 
-func (g *game) Layout(outsideWidth, outsideHeight int) (int, int) {
-	return WIDTH, HEIGHT
+// tileShape is the outline of a tile as DrawTile draws it
+func (g game) tileShape(x, y, rotation float32) shapes.Shape {
+	return shapes.Polygon(x, y, g.layout.tileRadius*1.5, 4, rotation-45)
 }
 
 func (g *game) Standby() {
@@ -420,101 +614,61 @@ func (g *game) Standby() {
 }
 
 func (g *game) End() {
-
-	var states [NUM_ROWS][NUM_COLS]TileState
-
 	g.RemoveTileWithState(MouseOverTile)
-
-	for r := 0; r < g.rows; r++ {
-		for c := 0; c < g.cols; c++ {
-			currentState := g.board[r][c].state
-			if currentState == AlphaTile || currentState == BetaTile || currentState == CenterTile {
-				// flip horizontally r
-				nr := g.rows - 1 - r
-				// flip vertically c
-				nc := g.cols - 1 - c
-				states[nr][nc] = currentState
-			}
-		}
+	g.playerPicked, g.playerPick = g.FindPlayerPosition()
+	if g.roundMode == MovementMode {
+		g.playerPicked, g.playerPick = g.CharacterTile()
 	}
 
-	for r := 0; r < g.rows; r++ {
-		for c := 0; c < g.cols; c++ {
-			currentState := states[r][c]
-			if currentState == AlphaTile || currentState == BetaTile || currentState == CenterTile {
-				g.board[r][c].state = currentState
-			}
-		}
+	if !g.boardFlipped {
+		g.Flip()
 	}
 
-	objectiveRow := 0
-	objectiveColumn := g.columnObjective * 2
-
-	for r := 0; r < g.rows; r++ {
-		if g.board[r][objectiveColumn].state == CenterTile {
-			objectiveRow = r
-			break
-		}
+	g.resolution = g.strategy.Resolve(g.originalStates.flipped(), g.symbolObjective, g.objectiveColumn())
+	if g.resolution.found && g.playerPicked && g.playerPick == g.resolution.spot {
+		g.win = true
 	}
 
-	var objectivePosition BoardPosition
-	found := false
-
-	posible := g.TilesAroundATileWithAnState(objectiveRow, objectiveColumn, g.symbolObjective)
-	possibles := len(posible)
-	if possibles > 0 {
-		if possibles == 1 {
-			found = true
-			objectivePosition = posible[0]
-		} else {
-			for _, p := range posible {
-				if len(g.TilesAroundATileWithAnState(p.row, p.column, CenterTile)) == 1 {
-					found = true
-					objectivePosition = p
-					break
-				}
-			}
-		}
+	g.mistake = NoMistake
+	if !g.win {
+		g.mistake = classifyMistake(g.strategy, g.originalStates, g.symbolObjective, g.objectiveColumn(), g.definition.markerColumns, g.playerPicked, g.playerPick)
+	}
+	g.collisions = g.findCollisions()
+	g.mistake = g.collisionMistake()
+	if g.mistake != NoMistake {
+		g.win = false
+	}
+	g.score = roundScore(g.win, g.hintsUsed)
+	if g.drillPhase != NoDrillPhase {
+		g.score = drillScore(g.preFlipCorrect(), g.win, g.hintsUsed)
+		g.stats.recordDrill(g.preFlipCorrect())
+	}
+	g.stats.record(g.win, g.mistake, g.score, g.hintsUsed)
 
+	// in the drill the board was already flipped while playing
+	g.endFlipping = g.drillPhase == NoDrillPhase
+	if g.endFlipping {
+		g.FlipAndReveal()
+	} else {
+		g.RevealResult()
 	}
 
-	if found {
-		g.objectiveSymbolPosition = objectivePosition
-		g.centerSymbolPosition = BoardPosition{row: objectiveRow, column: objectiveColumn}
-		playerFound, playerPosition := g.FindPlayerPosition()
-		if playerFound {
-			// check is between the center and objective
-
-			// if he need to be horizontally
-			if objectivePosition.row == g.centerSymbolPosition.row && g.centerSymbolPosition.row == playerPosition.row {
-				if objectivePosition.column < g.centerSymbolPosition.column {
-					if playerPosition.column > objectivePosition.column && playerPosition.column < g.centerSymbolPosition.column {
-						g.objectiveSymbolPosition = playerPosition
-						g.win = true
-					}
-				} else {
-					if playerPosition.column < objectivePosition.column && playerPosition.column > g.centerSymbolPosition.column {
-						g.objectiveSymbolPosition = playerPosition
-						g.win = true
-					}
-				}
-			} else if objectivePosition.column == g.centerSymbolPosition.column && g.centerSymbolPosition.column == playerPosition.column {
-				if objectivePosition.row < g.centerSymbolPosition.row {
-					if playerPosition.row > objectivePosition.row && playerPosition.row < g.centerSymbolPosition.row {
-						g.objectiveSymbolPosition = playerPosition
-						g.win = true
-					}
-				} else {
-					if playerPosition.row < objectivePosition.row && playerPosition.row > g.centerSymbolPosition.row {
-						g.objectiveSymbolPosition = playerPosition
-						g.win = true
-					}
-				}
+	g.state = EndState
+}
+
+// Flip does Panta Rhei on the board, keeping how it was before
+func (g *game) Flip() {
+	g.originalStates = g.States()
+	flipped := g.originalStates.flipped()
+	for r := 0; r < g.rows; r++ {
+		for c := 0; c < g.cols; c++ {
+			// the symbols move, so the tiles they leave are cleared too
+			if isSymbol(g.originalStates[r][c]) || isSymbol(flipped[r][c]) {
+				g.board[r][c].state = flipped[r][c]
 			}
 		}
 	}
-
-	g.state = EndState
+	g.boardFlipped = true
 }
 
 func (g *game) FindPlayerPosition() (bool, BoardPosition) {
@@ -528,159 +682,50 @@ func (g *game) FindPlayerPosition() (bool, BoardPosition) {
 	return false, BoardPosition{}
 }
 
-func (g game) TilesAroundATileWithAnState(row, column int, state TileState) []BoardPosition {
-	result := []BoardPosition{}
-
-	// look 2 up
-	if row > 1 {
-		if g.board[row-2][column].state == state {
-			result = append(result, BoardPosition{row: row - 2, column: column})
-		}
-	}
-	// look 2 down
-	if row < g.rows-2 {
-		if g.board[row+2][column].state == state {
-			result = append(result, BoardPosition{row: row + 2, column: column})
-		}
-	}
-	// look 2 left
-	if column > 1 {
-		if g.board[row][column-2].state == state {
-			result = append(result, BoardPosition{row: row, column: column - 2})
-		}
-	}
-	// look 2 right
-	if column < g.cols-2 {
-		if g.board[row][column+2].state == state {
-			result = append(result, BoardPosition{row: row, column: column + 2})
-		}
-	}
-
-	return result
-}
-
 func (g *game) Reset() {
-	const (
-		startX = TITLE_RADIUS * 3
-		startY = TITLE_RADIUS * 3
-	)
-
-	var x, y float32 = startX, startY
 	for r := 0; r < g.rows; r++ {
 		for c := 0; c < g.cols; c++ {
 			g.board[r][c].state = EmptyTile
-
 			g.board[r][c].rotation = 0
-			g.board[r][c].x = x
-			g.board[r][c].y = y
-			x += TITLE_RADIUS * 3
 		}
-		x = startX
-		y += TITLE_RADIUS * 2.5
-	}
-
-	g.board[1][1].state = InvalidTile
-	g.board[3][1].state = InvalidTile
-	g.board[1][3].state = InvalidTile
-	g.board[3][3].state = InvalidTile
-	g.board[1][5].state = InvalidTile
-	g.board[3][5].state = InvalidTile
-
-	boardNum := rand.Intn(5)
-
-	switch boardNum {
-	case 0:
-		g.SetTile(0, 0, BetaTile)
-		g.SetTile(0, 2, CenterTile)
-		g.SetTile(0, 4, AlphaTile)
-
-		g.SetTile(2, 0, CenterTile)
-		g.SetTile(2, 2, AlphaTile)
-		g.SetTile(2, 4, BetaTile)
-
-		g.SetTile(4, 0, BetaTile)
-		g.SetTile(4, 2, BetaTile)
-		g.SetTile(4, 4, CenterTile)
-
-		g.SetTile(6, 0, AlphaTile)
-		g.SetTile(6, 2, CenterTile)
-		g.SetTile(6, 4, AlphaTile)
-	case 1:
-		g.SetTile(0, 0, AlphaTile)
-		g.SetTile(0, 2, CenterTile)
-		g.SetTile(0, 4, BetaTile)
-
-		g.SetTile(2, 0, CenterTile)
-		g.SetTile(2, 2, BetaTile)
-		g.SetTile(2, 4, BetaTile)
-
-		g.SetTile(4, 0, AlphaTile)
-		g.SetTile(4, 2, AlphaTile)
-		g.SetTile(4, 4, CenterTile)
-
-		g.SetTile(6, 0, AlphaTile)
-		g.SetTile(6, 2, CenterTile)
-		g.SetTile(6, 4, BetaTile)
-	case 2:
-		g.SetTile(0, 0, AlphaTile)
-		g.SetTile(0, 2, CenterTile)
-		g.SetTile(0, 4, BetaTile)
-
-		g.SetTile(2, 0, BetaTile)
-		g.SetTile(2, 2, CenterTile)
-		g.SetTile(2, 4, BetaTile)
-
-		g.SetTile(4, 0, AlphaTile)
-		g.SetTile(4, 2, AlphaTile)
-		g.SetTile(4, 4, CenterTile)
-
-		g.SetTile(6, 0, CenterTile)
-		g.SetTile(6, 2, BetaTile)
-		g.SetTile(6, 4, AlphaTile)
-	case 3:
-		g.SetTile(0, 0, AlphaTile)
-		g.SetTile(0, 2, CenterTile)
-		g.SetTile(0, 4, BetaTile)
-
-		g.SetTile(2, 0, AlphaTile)
-		g.SetTile(2, 2, AlphaTile)
-		g.SetTile(2, 4, CenterTile)
-
-		g.SetTile(4, 0, CenterTile)
-		g.SetTile(4, 2, BetaTile)
-		g.SetTile(4, 4, BetaTile)
-
-		g.SetTile(6, 0, AlphaTile)
-		g.SetTile(6, 2, CenterTile)
-		g.SetTile(6, 4, BetaTile)
-	case 4:
-		g.SetTile(0, 0, CenterTile)
-		g.SetTile(0, 2, AlphaTile)
-		g.SetTile(0, 4, BetaTile)
-
-		g.SetTile(2, 0, BetaTile)
-		g.SetTile(2, 2, BetaTile)
-		g.SetTile(2, 4, CenterTile)
-
-		g.SetTile(4, 0, AlphaTile)
-		g.SetTile(4, 2, CenterTile)
-		g.SetTile(4, 4, AlphaTile)
-
-		g.SetTile(6, 0, BetaTile)
-		g.SetTile(6, 2, CenterTile)
-		g.SetTile(6, 4, AlphaTile)
+	}
+	g.PlaceTiles()
+	g.timeline.Start(SpawnAnimation, newTween(0, 1, g.clock.Now(), SPAWN_TIME, easeOutBack))
+
+	for _, h := range g.definition.holes {
+		g.board[h.row][h.column].state = InvalidTile
+	}
+
+	setup := g.definition.setups[rand.Intn(len(g.definition.setups))]
+	for _, t := range setup {
+		g.SetTile(t.column, t.row, t.state)
 	}
 
 	g.state = PlayingState
-	g.timeLeft = MAX_TIME
-	g.lastUpdateTime = time.Now()
+	g.casts = g.roundCasts()
+	g.timeLeft = castsTime(g.casts)
+	g.clock.SetScale(float64(g.settings.Speed) / 100)
 
 	// random alpha or beta
 	g.symbolObjective = TileState(rand.Intn(2) + 1)
 
-	// random 0, 1, 2, 3
-	g.columnObjective = rand.Intn(4)
+	// random marker
+	g.columnObjective = rand.Intn(NUM_MARKERS)
 	g.win = false
+	g.hintsUsed = 0
+	g.resolution = g.strategy.Resolve(g.States().flipped(), g.symbolObjective, g.objectiveColumn())
+	g.boardFlipped = false
+	g.drillPhase = NoDrillPhase
+	g.roundMode = g.settings.Mode
+	switch g.roundMode {
+	case DrillMode:
+		g.drillPhase = PreFlipPhase
+	case MovementMode:
+		g.startCharacter()
+	}
+	g.StartParty()
+
+	g.PlaySound(RoundStartSound)
 }
 
 func (g *game) RemoveTileWithState(state TileState) {
@@ -694,6 +739,9 @@ func (g *game) RemoveTileWithState(state TileState) {
 }
 
 func (g *game) SetTile(c int, r int, state TileState) {
+	if state == MouseOverTile && g.board[r][c].state != MouseOverTile {
+		g.timeline.Start(HoverAnimation, newTween(1, HOVER_SCALE, g.clock.Now(), HOVER_TIME, easeOutQuad))
+	}
 	switch state {
 	case PlayerTile, MouseOverTile:
 		g.RemoveTileWithState(state)
@@ -707,58 +755,30 @@ func (g *game) SetTile(c int, r int, state TileState) {
 func New(er embed.FS) ebiten.Game {
 	ebiten.SetWindowSize(WIDTH, HEIGHT)
 	ebiten.SetWindowTitle("Classical Concepts 2 Trainer")
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	ebiten.SetTPS(60)
 
-	// Load font
-	fontBytes, err := fs.ReadFile(er, "embed/fonts/default.ttf")
-	if err != nil {
-		panic(err)
-	}
-	font, err := truetype.Parse(fontBytes)
-	if err != nil {
-		panic(err)
-	}
-
-	defaultFont := truetype.NewFace(font, &truetype.Options{
-		Size: 70,
-		DPI:  90,
-	})
-
-	smallFont := truetype.NewFace(font, &truetype.Options{
-		Size: 50,
-		DPI:  90,
-	})
-
 	g := game{
-		board:       [NUM_ROWS][NUM_COLS]tile{},
-		rows:        NUM_ROWS,
-		cols:        NUM_COLS,
-		defaultFont: defaultFont,
-		smallFont:   smallFont,
-	}
-
+		layout:   layout{width: WIDTH, height: HEIGHT},
+		batch:    &shapes.Batch{},
+		sprites:  newSprites(),
+		clock:    newClock(ebitenTicks{}),
+		uiClock:  newClock(ebitenTicks{}),
+		timeline: newTimeline(),
+		fonts:    loadFonts(er, "embed/fonts/default.ttf", "embed/fonts/mplus-1p-regular.ttf"),
+		settings: loadSettings(),
+		sounds:   newSounds(),
+		stats:    loadStats(),
+	}
+
+	loadStrategies(er)
+	loadWaymarks()
+	g.strategy = findStrategy(g.settings.Strategy)
+	g.palette = findPalette(g.settings.Palette)
+	g.UseBoard(classicalConcepts)
 	g.Standby()
 
-	g.buttonX = WIDTH - (BUTTON_WIDTH * 1.5)
-	g.buttonY = (HEIGHT / 2) - (BUTTON_HEIGHT / 2)
-	g.buttonOver = false
-	g.buttonColor = darkGreen
-
-	g.objectiveX = WIDTH - 400
-	g.objectiveY = 100
-
-	g.aText = g.CreateTextImage("A", red, g.defaultFont)
-	g.bText = g.CreateTextImage("B", yellow, g.defaultFont)
-	g.cText = g.CreateTextImage("C", blue, g.defaultFont)
-	g.dText = g.CreateTextImage("D", purple, g.defaultFont)
-
-	g.buttonText = g.CreateTextImage("Try!", white, g.defaultFont)
-
-	g.alphaObjetiveText = g.CreateTextImage("Alpha", white, g.defaultFont)
-	g.betaObjetiveText = g.CreateTextImage("Beta", white, g.defaultFont)
-
-	g.winningText = g.CreateTextImage("Great Success!", green, g.defaultFont)
-	g.loosingText = g.CreateTextImage("Oh, my bad!", red, g.defaultFont)
+	g.language = resolveLanguage(g.settings.Language)
 
 	return &g
 }
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+const (
+	MAX_HINTS = 3
+	MAX_SCORE = 100
+	// score lost for each hint used
+	HINT_PENALTY = 25
+)
+
+type hintLevel int
+
+const (
+	NoHint hintLevel = iota
+	ColumnHint
+	HexagonHint
+	SymbolHint
+)
+
+func (g *game) UpdateHints() {
+	if !g.settings.Hints {
+		return
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyH) && g.hintsUsed < MAX_HINTS {
+		g.hintsUsed++
+		g.PlaySound(SelectSound)
+	}
+}
+
+// DrawHints draws the hints used so far, they point where things are now and
+// not where they will be after Panta Rhei
+func (g game) DrawHints(screen *ebiten.Image) {
+	if !g.settings.Hints {
+		return
+	}
+
+	res := g.resolution
+	level := hintLevel(g.hintsUsed)
+
+	if level >= ColumnHint {
+		g.DrawColumnHighlight(screen, g.objectiveColumn(), 1)
+	}
+	if res.found && level >= HexagonHint {
+		// before the flip, the hexagon that Panta Rhei will move into our column
+		hexagon := res.center
+		if !g.boardFlipped {
+			hexagon = g.mirrored(res.center)
+		}
+		g.DrawRing(screen, hexagon, g.palette.center)
+	}
+	if res.found && level >= SymbolHint {
+		t := g.board[res.symbol.row][res.symbol.column]
+		g.DrawRing(screen, res.symbol, white)
+		g.DrawSymbol(g.batch, t.x, t.y, g.layout.tileRadius/2, 0, g.symbolObjective)
+		g.batch.Flush(screen)
+	}
+
+	style := defaultTextStyle()
+	style.size = SMALL_FONT_SIZE
+	style.fit = g.layout.panel.width - MARGIN*2
+	button := g.layout.button
+	g.DrawText(screen, fmt.Sprintf(g.T(HintKeyMessage), g.hintsUsed, MAX_HINTS), button.x+button.width/2, button.y+button.height/2, style)
+}
+
+// roundScore is the score of a round, hints make it lower
+func roundScore(win bool, hintsUsed int) int {
+	if !win {
+		return 0
+	}
+	return MAX_SCORE - hintsUsed*HINT_PENALTY
+}
@@ -0,0 +1,535 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import "strings"
+
+type message int
+
+const (
+	TryMessage message = iota
+	SettingsMessage
+	BackMessage
+	AlphaMessage
+	BetaMessage
+	WinMessage
+	LoseMessage
+	PaletteMessage
+	ShapeOnlyMessage
+	LanguageMessage
+	OnMessage
+	OffMessage
+	AutoMessage
+	DefaultPaletteMessage
+	DeuteranopiaMessage
+	ProtanopiaMessage
+	TritanopiaMessage
+	HighContrastMessage
+	VolumeMessage
+	MuteMessage
+	ExplainMessage
+	NextMessage
+	PreviousMessage
+	CloseMessage
+	ExplainOriginalMessage
+	ExplainFlipMessage
+	ExplainHexagonMessage
+	ExplainSymbolMessage
+	ExplainSpotMessage
+	StatsMessage
+	RoundsMessage
+	WinsMessage
+	NoPickMistakeMessage
+	ShapeInsteadOfGapMistakeMessage
+	NoFlipMistakeMessage
+	SingleAxisFlipMistakeMessage
+	WrongSymbolMistakeMessage
+	WrongColumnMistakeMessage
+	UnknownMistakeMessage
+	HintsMessage
+	HintKeyMessage
+	ScoreMessage
+	HintsUsedMessage
+	AverageScoreMessage
+	ModeMessage
+	NormalModeMessage
+	DrillModeMessage
+	DrillPreFlipMessage
+	DrillPostFlipMessage
+	DrillResultMessage
+	RightMessage
+	WrongMessage
+	DrillStatsMessage
+	FlipTimeMessage
+	SpeedMessage
+	PausedMessage
+	ResumeMessage
+	RestartMessage
+	QuitMessage
+	MovementModeMessage
+	StartMessage
+	CenterStartMessage
+	MarkerStartMessage
+	RandomStartMessage
+	MovementHelpMessage
+	PartyMessage
+	PartyErrorsMessage
+	PartyDelayMessage
+	SharedTileMistakeMessage
+	BlockedTetherMistakeMessage
+	SharedTileMessage
+	BlockedTetherMessage
+	StrategyMessage
+	DefaultStrategyMessage
+	WaymarksMessage
+	AssignmentMessage
+	TextAssignmentMessage
+	IconsAssignmentMessage
+	QuizAssignmentMessage
+	ClassicalConceptsMessage
+	PantaRheiMessage
+	ConfirmCastMessage
+	CastChainMessage
+	EditWaymarkMessage
+	WaymarkLabelMessage
+	WaymarkShapeMessage
+	WaymarkColorMessage
+	WaymarkPositionMessage
+	CircleMessage
+	SquareMessage
+)
+
+const (
+	AUTO_LANGUAGE    = "auto"
+	DEFAULT_LANGUAGE = "en"
+)
+
+var languages = []string{"en", "ja", "fr", "de"}
+
+// languageNames are always shown in their own language
+var languageNames = map[string]string{
+	"en": "English",
+	"ja": "日本語",
+	"fr": "Français",
+	"de": "Deutsch",
+}
+
+var catalogue = map[string]map[message]string{
+	"en": {
+		TryMessage:                      "Try!",
+		SettingsMessage:                 "Settings",
+		BackMessage:                     "Back",
+		AlphaMessage:                    "Alpha",
+		BetaMessage:                     "Beta",
+		WinMessage:                      "Great Success!",
+		LoseMessage:                     "Oh, my bad!",
+		PaletteMessage:                  "Palette",
+		ShapeOnlyMessage:                "Shape only",
+		LanguageMessage:                 "Language",
+		OnMessage:                       "On",
+		OffMessage:                      "Off",
+		AutoMessage:                     "Auto",
+		DefaultPaletteMessage:           "Default",
+		DeuteranopiaMessage:             "Deuteranopia",
+		ProtanopiaMessage:               "Protanopia",
+		TritanopiaMessage:               "Tritanopia",
+		HighContrastMessage:             "High Contrast",
+		VolumeMessage:                   "Volume",
+		MuteMessage:                     "Mute",
+		ExplainMessage:                  "Explain",
+		NextMessage:                     "Next",
+		PreviousMessage:                 "Previous",
+		CloseMessage:                    "Close",
+		ExplainOriginalMessage:          "This is the board when Panta Rhei is cast.",
+		ExplainFlipMessage:              "Panta Rhei flips every shape to the opposite side of the board.",
+		ExplainHexagonMessage:           "Your column is %s: find the hexagon in it.",
+		ExplainSymbolMessage:            "Find the %s two tiles away from that hexagon.",
+		ExplainSpotMessage:              "Stand on the tile between them to take the tether.",
+		StatsMessage:                    "Stats",
+		RoundsMessage:                   "Rounds: %d",
+		WinsMessage:                     "Wins: %d (%d%%)",
+		NoPickMistakeMessage:            "You did not pick any tile.",
+		ShapeInsteadOfGapMistakeMessage: "You picked the shape instead of the gap between them.",
+		NoFlipMistakeMessage:            "You forgot the Panta Rhei flip.",
+		SingleAxisFlipMistakeMessage:    "You flipped only one axis of the board.",
+		WrongSymbolMistakeMessage:       "You used the wrong symbol.",
+		WrongColumnMistakeMessage:       "You used the wrong column.",
+		UnknownMistakeMessage:           "That tile does not match any usual mistake.",
+		HintsMessage:                    "Hints",
+		HintKeyMessage:                  "H: hint %d/%d",
+		ScoreMessage:                    "Score: %d",
+		HintsUsedMessage:                "Hints used: %d",
+		AverageScoreMessage:             "Average score: %d",
+		ModeMessage:                     "Mode",
+		NormalModeMessage:               "Normal",
+		DrillModeMessage:                "Pre-flip drill",
+		DrillPreFlipMessage:             "Mark your spot before the flip.",
+		DrillPostFlipMessage:            "Confirm your spot after the flip.",
+		DrillResultMessage:              "Before the flip: %s, after it: %s",
+		RightMessage:                    "right",
+		WrongMessage:                    "wrong",
+		DrillStatsMessage:               "Right before the flip: %d of %d",
+		FlipTimeMessage:                 "Flip time",
+		SpeedMessage:                    "Speed",
+		PausedMessage:                   "Paused",
+		ResumeMessage:                   "Resume",
+		RestartMessage:                  "Restart",
+		QuitMessage:                     "Quit",
+		MovementModeMessage:             "Movement",
+		StartMessage:                    "Start",
+		CenterStartMessage:              "Center",
+		MarkerStartMessage:              "Own marker",
+		RandomStartMessage:              "Random",
+		MovementHelpMessage:             "Move with WASD, the arrows or a gamepad.",
+		PartyMessage:                    "Simulated party",
+		PartyErrorsMessage:              "Party mistakes",
+		PartyDelayMessage:               "Party reaction",
+		SharedTileMistakeMessage:        "You were right, but someone else was on your tile.",
+		BlockedTetherMistakeMessage:     "You stood in the spot of another player.",
+		SharedTileMessage:               "Same tile as %s.",
+		BlockedTetherMessage:            "Blocking the tether of %s.",
+		StrategyMessage:                 "Strategy",
+		DefaultStrategyMessage:          "Standard",
+		WaymarksMessage:                 "Waymarks",
+		AssignmentMessage:               "Assignment",
+		TextAssignmentMessage:           "Text",
+		IconsAssignmentMessage:          "Icons",
+		QuizAssignmentMessage:           "Quiz (icons only)",
+		ClassicalConceptsMessage:        "Classical Concepts",
+		PantaRheiMessage:                "Panta Rhei",
+		ConfirmCastMessage:              "Confirm",
+		CastChainMessage:                "Real cast timings",
+		EditWaymarkMessage:              "Edit waymark",
+		WaymarkLabelMessage:             "Waymark label",
+		WaymarkShapeMessage:             "Waymark shape",
+		WaymarkColorMessage:             "Waymark color",
+		WaymarkPositionMessage:          "Waymark column",
+		CircleMessage:                   "Circle",
+		SquareMessage:                   "Square",
+	},
+	"ja": {
+		TryMessage:                      "スタート",
+		SettingsMessage:                 "設定",
+		BackMessage:                     "戻る",
+		AlphaMessage:                    "アルファ",
+		BetaMessage:                     "ベータ",
+		WinMessage:                      "大成功！",
+		LoseMessage:                     "しまった！",
+		PaletteMessage:                  "配色",
+		ShapeOnlyMessage:                "形で表示",
+		LanguageMessage:                 "言語",
+		OnMessage:                       "オン",
+		OffMessage:                      "オフ",
+		AutoMessage:                     "自動",
+		DefaultPaletteMessage:           "標準",
+		DeuteranopiaMessage:             "2型色覚",
+		ProtanopiaMessage:               "1型色覚",
+		TritanopiaMessage:               "3型色覚",
+		HighContrastMessage:             "ハイコントラスト",
+		VolumeMessage:                   "音量",
+		MuteMessage:                     "ミュート",
+		ExplainMessage:                  "解説",
+		NextMessage:                     "次へ",
+		PreviousMessage:                 "前へ",
+		CloseMessage:                    "閉じる",
+		ExplainOriginalMessage:          "パンタレイ詠唱時の盤面です。",
+		ExplainFlipMessage:              "パンタレイで全ての図形が盤面の反対側へ移動します。",
+		ExplainHexagonMessage:           "担当の列は%sです。その列の六角形を探します。",
+		ExplainSymbolMessage:            "その六角形から2マス離れた%sを探します。",
+		ExplainSpotMessage:              "その間のマスに立って線を受けます。",
+		StatsMessage:                    "統計",
+		RoundsMessage:                   "ラウンド: %d",
+		WinsMessage:                     "成功: %d (%d%%)",
+		NoPickMistakeMessage:            "マスを選んでいません。",
+		ShapeInsteadOfGapMistakeMessage: "間のマスではなく図形を選んでいます。",
+		NoFlipMistakeMessage:            "パンタレイの反転を忘れています。",
+		SingleAxisFlipMistakeMessage:    "盤面を片方の軸だけ反転しています。",
+		WrongSymbolMistakeMessage:       "違う記号を使っています。",
+		WrongColumnMistakeMessage:       "違う列を使っています。",
+		UnknownMistakeMessage:           "よくあるミスのどれにも当てはまりません。",
+		HintsMessage:                    "ヒント",
+		HintKeyMessage:                  "H: ヒント %d/%d",
+		ScoreMessage:                    "スコア: %d",
+		HintsUsedMessage:                "使ったヒント: %d",
+		AverageScoreMessage:             "平均スコア: %d",
+		ModeMessage:                     "モード",
+		NormalModeMessage:               "通常",
+		DrillModeMessage:                "反転前ドリル",
+		DrillPreFlipMessage:             "反転前の位置を選んでください。",
+		DrillPostFlipMessage:            "反転後の位置を確定してください。",
+		DrillResultMessage:              "反転前: %s、反転後: %s",
+		RightMessage:                    "正解",
+		WrongMessage:                    "不正解",
+		DrillStatsMessage:               "反転前の正解: %d / %d",
+		FlipTimeMessage:                 "反転時間",
+		SpeedMessage:                    "速度",
+		PausedMessage:                   "一時停止中",
+		ResumeMessage:                   "再開",
+		RestartMessage:                  "やり直す",
+		QuitMessage:                     "終了",
+		MovementModeMessage:             "移動",
+		StartMessage:                    "開始位置",
+		CenterStartMessage:              "中央",
+		MarkerStartMessage:              "自分のマーカー",
+		RandomStartMessage:              "ランダム",
+		MovementHelpMessage:             "WASD、矢印キー、ゲームパッドで移動。",
+		PartyMessage:                    "仮想パーティ",
+		PartyErrorsMessage:              "パーティのミス率",
+		PartyDelayMessage:               "パーティの反応時間",
+		SharedTileMistakeMessage:        "正解でしたが、他のプレイヤーと同じマスにいました。",
+		BlockedTetherMistakeMessage:     "他のプレイヤーの位置に立っていました。",
+		SharedTileMessage:               "%sと同じマス。",
+		BlockedTetherMessage:            "%sの線を遮っています。",
+		StrategyMessage:                 "攻略法",
+		DefaultStrategyMessage:          "標準",
+		WaymarksMessage:                 "フィールドマーカー",
+		AssignmentMessage:               "割り当て表示",
+		TextAssignmentMessage:           "テキスト",
+		IconsAssignmentMessage:          "アイコン",
+		QuizAssignmentMessage:           "クイズ (アイコンのみ)",
+		ClassicalConceptsMessage:        "古典概念",
+		PantaRheiMessage:                "パンタ・レイ",
+		ConfirmCastMessage:              "確定",
+		CastChainMessage:                "実際の詠唱時間",
+		EditWaymarkMessage:              "編集するマーカー",
+		WaymarkLabelMessage:             "マーカーの名前",
+		WaymarkShapeMessage:             "マーカーの形",
+		WaymarkColorMessage:             "マーカーの色",
+		WaymarkPositionMessage:          "マーカーの列",
+		CircleMessage:                   "円",
+		SquareMessage:                   "四角",
+	},
+	"fr": {
+		TryMessage:                      "Essayer !",
+		SettingsMessage:                 "Paramètres",
+		BackMessage:                     "Retour",
+		AlphaMessage:                    "Alpha",
+		BetaMessage:                     "Bêta",
+		WinMessage:                      "Grand succès !",
+		LoseMessage:                     "Oups, raté !",
+		PaletteMessage:                  "Palette",
+		ShapeOnlyMessage:                "Formes seules",
+		LanguageMessage:                 "Langue",
+		OnMessage:                       "Activé",
+		OffMessage:                      "Désactivé",
+		AutoMessage:                     "Auto",
+		DefaultPaletteMessage:           "Par défaut",
+		DeuteranopiaMessage:             "Deutéranopie",
+		ProtanopiaMessage:               "Protanopie",
+		TritanopiaMessage:               "Tritanopie",
+		HighContrastMessage:             "Contraste élevé",
+		VolumeMessage:                   "Volume",
+		MuteMessage:                     "Muet",
+		ExplainMessage:                  "Expliquer",
+		NextMessage:                     "Suivant",
+		PreviousMessage:                 "Précédent",
+		CloseMessage:                    "Fermer",
+		ExplainOriginalMessage:          "Voici le plateau au moment où Panta Rhei est lancé.",
+		ExplainFlipMessage:              "Panta Rhei envoie chaque forme du côté opposé du plateau.",
+		ExplainHexagonMessage:           "Votre colonne est %s : trouvez son hexagone.",
+		ExplainSymbolMessage:            "Trouvez le symbole %s à deux cases de cet hexagone.",
+		ExplainSpotMessage:              "Placez-vous sur la case entre les deux pour prendre le lien.",
+		StatsMessage:                    "Statistiques",
+		RoundsMessage:                   "Manches : %d",
+		WinsMessage:                     "Réussites : %d (%d %%)",
+		NoPickMistakeMessage:            "Vous n'avez choisi aucune case.",
+		ShapeInsteadOfGapMistakeMessage: "Vous avez choisi la forme au lieu de la case entre les deux.",
+		NoFlipMistakeMessage:            "Vous avez oublié l'inversion de Panta Rhei.",
+		SingleAxisFlipMistakeMessage:    "Vous n'avez inversé qu'un seul axe du plateau.",
+		WrongSymbolMistakeMessage:       "Vous avez utilisé le mauvais symbole.",
+		WrongColumnMistakeMessage:       "Vous avez utilisé la mauvaise colonne.",
+		UnknownMistakeMessage:           "Cette case ne correspond à aucune erreur habituelle.",
+		HintsMessage:                    "Indices",
+		HintKeyMessage:                  "H : indice %d/%d",
+		ScoreMessage:                    "Score : %d",
+		HintsUsedMessage:                "Indices utilisés : %d",
+		AverageScoreMessage:             "Score moyen : %d",
+		ModeMessage:                     "Mode",
+		NormalModeMessage:               "Normal",
+		DrillModeMessage:                "Exercice avant inversion",
+		DrillPreFlipMessage:             "Marquez votre case avant l'inversion.",
+		DrillPostFlipMessage:            "Confirmez votre case après l'inversion.",
+		DrillResultMessage:              "Avant l'inversion : %s, après : %s",
+		RightMessage:                    "juste",
+		WrongMessage:                    "faux",
+		DrillStatsMessage:               "Justes avant l'inversion : %d sur %d",
+		FlipTimeMessage:                 "Durée de l'inversion",
+		SpeedMessage:                    "Vitesse",
+		PausedMessage:                   "En pause",
+		ResumeMessage:                   "Reprendre",
+		RestartMessage:                  "Recommencer",
+		QuitMessage:                     "Quitter",
+		MovementModeMessage:             "Déplacement",
+		StartMessage:                    "Départ",
+		CenterStartMessage:              "Centre",
+		MarkerStartMessage:              "Son marqueur",
+		RandomStartMessage:              "Aléatoire",
+		MovementHelpMessage:             "Déplacez-vous avec WASD, les flèches ou une manette.",
+		PartyMessage:                    "Groupe simulé",
+		PartyErrorsMessage:              "Erreurs du groupe",
+		PartyDelayMessage:               "Réaction du groupe",
+		SharedTileMistakeMessage:        "C'était juste, mais quelqu'un d'autre était sur votre case.",
+		BlockedTetherMistakeMessage:     "Vous étiez sur la case d'un autre joueur.",
+		SharedTileMessage:               "Même case que %s.",
+		BlockedTetherMessage:            "Bloque le lien de %s.",
+		StrategyMessage:                 "Stratégie",
+		DefaultStrategyMessage:          "Standard",
+		WaymarksMessage:                 "Marqueurs au sol",
+		AssignmentMessage:               "Affichage du rôle",
+		TextAssignmentMessage:           "Texte",
+		IconsAssignmentMessage:          "Icônes",
+		QuizAssignmentMessage:           "Quiz (icônes seules)",
+		ClassicalConceptsMessage:        "Concepts classiques",
+		PantaRheiMessage:                "Panta rhei",
+		ConfirmCastMessage:              "Confirmation",
+		CastChainMessage:                "Durées d'incantation réelles",
+		EditWaymarkMessage:              "Modifier le marqueur",
+		WaymarkLabelMessage:             "Nom du marqueur",
+		WaymarkShapeMessage:             "Forme du marqueur",
+		WaymarkColorMessage:             "Couleur du marqueur",
+		WaymarkPositionMessage:          "Colonne du marqueur",
+		CircleMessage:                   "Cercle",
+		SquareMessage:                   "Carré",
+	},
+	"de": {
+		TryMessage:                      "Los!",
+		SettingsMessage:                 "Einstellungen",
+		BackMessage:                     "Zurück",
+		AlphaMessage:                    "Alpha",
+		BetaMessage:                     "Beta",
+		WinMessage:                      "Großer Erfolg!",
+		LoseMessage:                     "Hoppla, daneben!",
+		PaletteMessage:                  "Farbpalette",
+		ShapeOnlyMessage:                "Nur Formen",
+		LanguageMessage:                 "Sprache",
+		OnMessage:                       "An",
+		OffMessage:                      "Aus",
+		AutoMessage:                     "Automatisch",
+		DefaultPaletteMessage:           "Standard",
+		DeuteranopiaMessage:             "Deuteranopie",
+		ProtanopiaMessage:               "Protanopie",
+		TritanopiaMessage:               "Tritanopie",
+		HighContrastMessage:             "Hoher Kontrast",
+		VolumeMessage:                   "Lautstärke",
+		MuteMessage:                     "Stumm",
+		ExplainMessage:                  "Erklären",
+		NextMessage:                     "Weiter",
+		PreviousMessage:                 "Zurück",
+		CloseMessage:                    "Schließen",
+		ExplainOriginalMessage:          "So sieht das Feld aus, wenn Panta Rhei gewirkt wird.",
+		ExplainFlipMessage:              "Panta Rhei spiegelt jede Form auf die gegenüberliegende Seite des Feldes.",
+		ExplainHexagonMessage:           "Deine Spalte ist %s: Finde das Sechseck darin.",
+		ExplainSymbolMessage:            "Finde das Symbol %s zwei Felder von diesem Sechseck entfernt.",
+		ExplainSpotMessage:              "Stell dich auf das Feld dazwischen, um die Verbindung zu nehmen.",
+		StatsMessage:                    "Statistik",
+		RoundsMessage:                   "Runden: %d",
+		WinsMessage:                     "Erfolge: %d (%d %%)",
+		NoPickMistakeMessage:            "Du hast kein Feld gewählt.",
+		ShapeInsteadOfGapMistakeMessage: "Du hast die Form statt des Feldes dazwischen gewählt.",
+		NoFlipMistakeMessage:            "Du hast die Spiegelung durch Panta Rhei vergessen.",
+		SingleAxisFlipMistakeMessage:    "Du hast das Feld nur an einer Achse gespiegelt.",
+		WrongSymbolMistakeMessage:       "Du hast das falsche Symbol genommen.",
+		WrongColumnMistakeMessage:       "Du hast die falsche Spalte genommen.",
+		UnknownMistakeMessage:           "Dieses Feld passt zu keinem üblichen Fehler.",
+		HintsMessage:                    "Hinweise",
+		HintKeyMessage:                  "H: Hinweis %d/%d",
+		ScoreMessage:                    "Punkte: %d",
+		HintsUsedMessage:                "Genutzte Hinweise: %d",
+		AverageScoreMessage:             "Durchschnittliche Punkte: %d",
+		ModeMessage:                     "Modus",
+		NormalModeMessage:               "Normal",
+		DrillModeMessage:                "Übung vor der Spiegelung",
+		DrillPreFlipMessage:             "Markiere dein Feld vor der Spiegelung.",
+		DrillPostFlipMessage:            "Bestätige dein Feld nach der Spiegelung.",
+		DrillResultMessage:              "Vor der Spiegelung: %s, danach: %s",
+		RightMessage:                    "richtig",
+		WrongMessage:                    "falsch",
+		DrillStatsMessage:               "Vor der Spiegelung richtig: %d von %d",
+		FlipTimeMessage:                 "Spiegelungsdauer",
+		SpeedMessage:                    "Geschwindigkeit",
+		PausedMessage:                   "Pausiert",
+		ResumeMessage:                   "Weiter",
+		RestartMessage:                  "Neustart",
+		QuitMessage:                     "Beenden",
+		MovementModeMessage:             "Bewegung",
+		StartMessage:                    "Start",
+		CenterStartMessage:              "Mitte",
+		MarkerStartMessage:              "Eigene Markierung",
+		RandomStartMessage:              "Zufällig",
+		MovementHelpMessage:             "Bewege dich mit WASD, den Pfeiltasten oder einem Gamepad.",
+		PartyMessage:                    "Simulierte Gruppe",
+		PartyErrorsMessage:              "Fehler der Gruppe",
+		PartyDelayMessage:               "Reaktion der Gruppe",
+		SharedTileMistakeMessage:        "Richtig, aber jemand anderes stand auf deinem Feld.",
+		BlockedTetherMistakeMessage:     "Du standest auf dem Feld eines anderen Spielers.",
+		SharedTileMessage:               "Gleiches Feld wie %s.",
+		BlockedTetherMessage:            "Blockiert die Verbindung von %s.",
+		StrategyMessage:                 "Strategie",
+		DefaultStrategyMessage:          "Standard",
+		WaymarksMessage:                 "Wegmarken",
+		AssignmentMessage:               "Zuweisung",
+		TextAssignmentMessage:           "Text",
+		IconsAssignmentMessage:          "Symbole",
+		QuizAssignmentMessage:           "Quiz (nur Symbole)",
+		ClassicalConceptsMessage:        "Klassische Konzepte",
+		PantaRheiMessage:                "Panta Rhei",
+		ConfirmCastMessage:              "Bestätigen",
+		CastChainMessage:                "Echte Zauberzeiten",
+		EditWaymarkMessage:              "Wegmarke bearbeiten",
+		WaymarkLabelMessage:             "Name der Wegmarke",
+		WaymarkShapeMessage:             "Form der Wegmarke",
+		WaymarkColorMessage:             "Farbe der Wegmarke",
+		WaymarkPositionMessage:          "Spalte der Wegmarke",
+		CircleMessage:                   "Kreis",
+		SquareMessage:                   "Quadrat",
+	},
+}
+
+// resolveLanguage turns the language setting into one of our languages, asking
+// the OS or browser when set to auto
+func resolveLanguage(setting string) string {
+	if setting == AUTO_LANGUAGE || setting == "" {
+		setting = systemLocale()
+	}
+	// locales come as fr-FR, de_DE.UTF-8 and so on
+	setting = strings.ToLower(setting)
+	for _, l := range languages {
+		if strings.HasPrefix(setting, l) {
+			return l
+		}
+	}
+	return DEFAULT_LANGUAGE
+}
+
+// T returns the text of a message in the current language, falling back to
+// english for anything not translated
+func (g game) T(m message) string {
+	return translate(g.language, m)
+}
+
+func translate(language string, m message) string {
+	if text, ok := catalogue[language][m]; ok {
+		return text
+	}
+	return catalogue[DEFAULT_LANGUAGE][m]
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import "math"
+
+const (
+	// tile spacing and footprint, in tile radius units
+	TILE_STEP_X    = 3
+	TILE_STEP_Y    = 2.5
+	TILE_FOOTPRINT = 3
+	// room reserved above the board for the markers and below it for the time
+	// bar, in tile radius units
+	MARKERS_SPACE = 2
+	BAR_SPACE     = 2
+	// fraction of the screen used by the side/bottom panel
+	PANEL_RATIO = 0.3
+	MARGIN      = 20
+	MAX_BUTTONS = 4
+)
+
+type rect struct {
+	x, y, width, height float32
+}
+
+func (r rect) hit(x, y float32) bool {
+	return x > r.x && x < r.x+r.width && y > r.y && y < r.y+r.height
+}
+
+// layout holds every screen position, computed from the board geometry and the
+// actual screen size, so nothing else needs to know about pixels
+type layout struct {
+	width, height float32
+	portrait      bool
+	// scale of the text and UI relative to the 1920x1080 design
+	scale      float32
+	tileRadius float32
+	boardX     float32
+	boardY     float32
+	stepX      float32
+	stepY      float32
+	markersY   float32
+	panel      rect
+	menu       rect
+	objectiveX float32
+	objectiveY float32
+	statusX    float32
+	statusY    float32
+	caption    rect
+	bar        rect
+	// first button, the following ones go below it or, in portrait, beside it
+	button     rect
+	buttonStep float32
+}
+
+func newLayout(width, height float32, rows, cols int) layout {
+	l := layout{
+		width:    width,
+		height:   height,
+		portrait: height > width,
+	}
+
+	boardUnitsW := float32(cols-1)*TILE_STEP_X + TILE_FOOTPRINT
+	boardUnitsH := float32(rows-1)*TILE_STEP_Y + TILE_FOOTPRINT + MARKERS_SPACE
+	if !l.portrait {
+		boardUnitsH += BAR_SPACE
+	}
+
+	var boardArea rect
+	if l.portrait {
+		panelHeight := height * PANEL_RATIO
+		boardArea = rect{x: 0, y: 0, width: width, height: height - panelHeight}
+		l.panel = rect{x: 0, y: height - panelHeight, width: width, height: panelHeight}
+	} else {
+		panelWidth := width * PANEL_RATIO
+		boardArea = rect{x: 0, y: 0, width: width - panelWidth, height: height}
+		l.panel = rect{x: width - panelWidth, y: 0, width: panelWidth, height: height}
+	}
+
+	l.tileRadius = float32(math.Min(
+		float64((boardArea.width-MARGIN*2)/boardUnitsW),
+		float64((boardArea.height-MARGIN*2)/boardUnitsH),
+	))
+	l.scale = float32(math.Min(float64(width)/WIDTH, float64(height)/HEIGHT))
+	if l.portrait {
+		l.scale = float32(math.Min(float64(width)/HEIGHT, float64(height)/WIDTH))
+	}
+
+	l.menu = boardArea
+
+	l.stepX = l.tileRadius * TILE_STEP_X
+	l.stepY = l.tileRadius * TILE_STEP_Y
+
+	// center the board in its area, markers included
+	boardW := boardUnitsW * l.tileRadius
+	boardH := boardUnitsH * l.tileRadius
+	left := boardArea.x + (boardArea.width-boardW)/2
+	top := boardArea.y + (boardArea.height-boardH)/2
+
+	l.markersY = top + l.tileRadius*MARKERS_SPACE/2
+	l.boardX = left + l.tileRadius*TILE_FOOTPRINT/2
+	l.boardY = top + l.tileRadius*MARKERS_SPACE + l.tileRadius*TILE_FOOTPRINT/2
+
+	buttonW := BUTTON_WIDTH * l.scale
+	buttonH := BUTTON_HEIGHT * l.scale
+	if l.portrait {
+		buttonW = float32(math.Min(float64(buttonW), float64((l.panel.width-MARGIN*4)/MAX_BUTTONS)))
+		buttonH = float32(math.Min(float64(buttonH), float64(l.panel.height*0.2)))
+		l.buttonStep = buttonW + MARGIN
+		l.button = rect{
+			x:      l.panel.x + (l.panel.width-l.buttonStep*MAX_BUTTONS+MARGIN)/2,
+			y:      l.panel.y + l.panel.height*0.85 - buttonH/2,
+			width:  buttonW,
+			height: buttonH,
+		}
+	} else {
+		l.buttonStep = buttonH * 1.3
+		l.button = rect{
+			x:      l.panel.x + (l.panel.width-buttonW)/2,
+			y:      l.panel.y + l.panel.height*0.48,
+			width:  buttonW,
+			height: buttonH,
+		}
+	}
+
+	l.objectiveX = l.panel.x + l.panel.width/2
+	l.objectiveY = l.panel.y + l.panel.height*0.12
+
+	l.statusX = l.panel.x + l.panel.width/2
+	l.statusY = l.panel.y + l.panel.height*0.3
+	if l.portrait {
+		l.statusY = l.panel.y + l.panel.height*0.55
+	}
+
+	// the caption goes from under the objective to the buttons
+	captionTop := l.panel.y + l.panel.height*0.2
+	l.caption = rect{
+		x:      l.panel.x + MARGIN,
+		y:      captionTop,
+		width:  l.panel.width - MARGIN*2,
+		height: l.button.y - captionTop - MARGIN,
+	}
+
+	if l.portrait {
+		l.bar = rect{
+			x:      l.panel.x + MARGIN,
+			y:      l.panel.y + l.panel.height*0.35,
+			width:  l.panel.width - MARGIN*2,
+			height: l.tileRadius,
+		}
+	} else {
+		l.bar = rect{
+			x:      left,
+			y:      top + boardH - l.tileRadius*BAR_SPACE + l.tileRadius/2,
+			width:  boardW,
+			height: l.tileRadius,
+		}
+	}
+
+	return l
+}
+
+// buttonRect returns the area of the button in a position
+func (l layout) buttonRect(index int) rect {
+	b := l.button
+	if l.portrait {
+		b.x += l.buttonStep * float32(index)
+	} else {
+		b.y += l.buttonStep * float32(index)
+	}
+	return b
+}
+
+// tilePosition returns the screen center of a board tile
+func (l layout) tilePosition(row, column int) (float32, float32) {
+	return l.boardX + float32(column)*l.stepX, l.boardY + float32(row)*l.stepY
+}
+
+// markerX returns the screen x of the marker over a board column, so markers
+// always line up with the columns they refer to
+func (l layout) markerX(column int) float32 {
+	x, _ := l.tilePosition(0, column)
+	return x
+}
@@ -0,0 +1,294 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"fmt"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const MENU_LINE_HEIGHT = 110
+
+type menuEntry struct {
+	label  func() string
+	change func(delta int)
+}
+
+func (g game) onOff(value bool) string {
+	if value {
+		return g.T(OnMessage)
+	}
+	return g.T(OffMessage)
+}
+
+func (g *game) SettingsEntries() []menuEntry {
+	entries := []menuEntry{
+		{
+			label: func() string { return g.T(ModeMessage) + ": " + g.T(modeMessages[g.settings.Mode]) },
+			change: func(delta int) {
+				current := 0
+				for i, m := range modes {
+					if m == g.settings.Mode {
+						current = i
+					}
+				}
+				current = (current + delta + len(modes)) % len(modes)
+				g.settings.Mode = modes[current]
+			},
+		},
+		{
+			label: func() string { return g.T(AssignmentMessage) + ": " + g.T(assignmentMessages[g.settings.Assignment]) },
+			change: func(delta int) {
+				current := 0
+				for i, a := range assignmentDisplays {
+					if a == g.settings.Assignment {
+						current = i
+					}
+				}
+				current = (current + delta + len(assignmentDisplays)) % len(assignmentDisplays)
+				g.settings.Assignment = assignmentDisplays[current]
+			},
+		},
+		{
+			label: func() string { return g.T(StartMessage) + ": " + g.T(startMessages[g.settings.Start]) },
+			change: func(delta int) {
+				current := 0
+				for i, s := range startPositions {
+					if s == g.settings.Start {
+						current = i
+					}
+				}
+				current = (current + delta + len(startPositions)) % len(startPositions)
+				g.settings.Start = startPositions[current]
+			},
+		},
+		{
+			label: func() string { return g.T(StrategyMessage) + ": " + g.strategy.Label(g.language) },
+			change: func(delta int) {
+				current := 0
+				for i, id := range strategyIDs {
+					if id == g.strategy.ID() {
+						current = i
+					}
+				}
+				current = (current + delta + len(strategyIDs)) % len(strategyIDs)
+				g.strategy = strategies[strategyIDs[current]]
+				g.settings.Strategy = g.strategy.ID()
+			},
+		},
+		{
+			label: func() string {
+				if g.settings.Waymarks == AUTO_WAYMARKS {
+					return g.T(WaymarksMessage) + ": " + g.T(AutoMessage)
+				}
+				labels := ""
+				for _, w := range g.waymarks() {
+					labels += " " + w.Label
+				}
+				return g.T(WaymarksMessage) + ":" + labels
+			},
+			change: func(delta int) {
+				ids := []string{AUTO_WAYMARKS}
+				for _, set := range waymarkSets {
+					ids = append(ids, set.ID)
+				}
+				current := 0
+				for i, id := range ids {
+					if id == g.settings.Waymarks {
+						current = i
+					}
+				}
+				current = (current + delta + len(ids)) % len(ids)
+				g.settings.Waymarks = ids[current]
+			},
+		},
+	}
+	entries = append(entries, g.WaymarkEntries()...)
+	return append(entries, []menuEntry{
+		{
+			label: func() string { return g.T(PaletteMessage) + ": " + g.T(g.palette.label) },
+			change: func(delta int) {
+				current := 0
+				for i, p := range palettes {
+					if p.name == g.palette.name {
+						current = i
+					}
+				}
+				current = (current + delta + len(palettes)) % len(palettes)
+				g.palette = palettes[current]
+				g.settings.Palette = g.palette.name
+			},
+		},
+		{
+			label:  func() string { return g.T(ShapeOnlyMessage) + ": " + g.onOff(g.settings.ShapeOnly) },
+			change: func(int) { g.settings.ShapeOnly = !g.settings.ShapeOnly },
+		},
+		{
+			label: func() string {
+				if g.settings.Language == AUTO_LANGUAGE {
+					return g.T(LanguageMessage) + ": " + g.T(AutoMessage)
+				}
+				return g.T(LanguageMessage) + ": " + languageNames[g.settings.Language]
+			},
+			change: func(delta int) {
+				options := append([]string{AUTO_LANGUAGE}, languages...)
+				current := 0
+				for i, l := range options {
+					if l == g.settings.Language {
+						current = i
+					}
+				}
+				current = (current + delta + len(options)) % len(options)
+				g.settings.Language = options[current]
+				g.language = resolveLanguage(g.settings.Language)
+			},
+		},
+		{
+			label: func() string { return fmt.Sprintf("%s: %d", g.T(VolumeMessage), g.settings.Volume) },
+			change: func(delta int) {
+				g.settings.Volume = (g.settings.Volume + delta + MAX_VOLUME + 1) % (MAX_VOLUME + 1)
+				g.PlaySound(SelectSound)
+			},
+		},
+		{
+			label:  func() string { return g.T(MuteMessage) + ": " + g.onOff(g.settings.Mute) },
+			change: func(int) { g.settings.Mute = !g.settings.Mute },
+		},
+		{
+			label:  func() string { return g.T(HintsMessage) + ": " + g.onOff(g.settings.Hints) },
+			change: func(int) { g.settings.Hints = !g.settings.Hints },
+		},
+		{
+			label: func() string { return fmt.Sprintf("%s: %.1fs", g.T(FlipTimeMessage), g.settings.FlipTime) },
+			change: func(delta int) {
+				steps := int(MAX_FLIP_TIME/FLIP_TIME_STEP) + 1
+				step := int(g.settings.FlipTime/FLIP_TIME_STEP+0.5) + delta
+				g.settings.FlipTime = float64((step+steps)%steps) * FLIP_TIME_STEP
+			},
+		},
+		{
+			label:  func() string { return g.T(CastChainMessage) + ": " + g.onOff(g.settings.CastChain) },
+			change: func(int) { g.settings.CastChain = !g.settings.CastChain },
+		},
+		{
+			label: func() string { return fmt.Sprintf("%s: %d%%", g.T(SpeedMessage), g.settings.Speed) },
+			change: func(delta int) {
+				steps := (MAX_SPEED-MIN_SPEED)/SPEED_STEP + 1
+				step := (g.settings.Speed-MIN_SPEED)/SPEED_STEP + delta
+				g.settings.Speed = MIN_SPEED + (step+steps)%steps*SPEED_STEP
+			},
+		},
+		{
+			label:  func() string { return g.T(PartyMessage) + ": " + g.onOff(g.settings.Party) },
+			change: func(int) { g.settings.Party = !g.settings.Party },
+		},
+		{
+			label: func() string { return fmt.Sprintf("%s: %d%%", g.T(PartyErrorsMessage), g.settings.PartyErrors) },
+			change: func(delta int) {
+				steps := MAX_PARTY_ERRORS/PARTY_ERRORS_STEP + 1
+				step := g.settings.PartyErrors/PARTY_ERRORS_STEP + delta
+				g.settings.PartyErrors = (step + steps) % steps * PARTY_ERRORS_STEP
+			},
+		},
+		{
+			label: func() string { return fmt.Sprintf("%s: %.1fs", g.T(PartyDelayMessage), g.settings.PartyDelay) },
+			change: func(delta int) {
+				steps := int(MAX_PARTY_DELAY/PARTY_DELAY_STEP) + 1
+				step := int(g.settings.PartyDelay/PARTY_DELAY_STEP+0.5) + delta
+				g.settings.PartyDelay = float64((step+steps)%steps) * PARTY_DELAY_STEP
+			},
+		},
+	}...)
+}
+
+func (g *game) OpenSettings() {
+	g.menuReturnState = g.state
+	g.menuSelected = 0
+	g.state = SettingsState
+}
+
+func (g *game) CloseSettings() {
+	g.settings.save()
+	g.state = g.menuReturnState
+}
+
+// menuLineScale returns how much the lines of a menu with some entries need to
+// shrink so all of them fit
+func (g game) menuLineScale(count int) float32 {
+	return min(1, g.layout.menu.height*0.85/(MENU_LINE_HEIGHT*g.layout.scale*float32(count)))
+}
+
+func (g game) menuEntryArea(index, count int) rect {
+	lineHeight := MENU_LINE_HEIGHT * g.layout.scale * g.menuLineScale(count)
+	return rect{
+		x:      g.layout.menu.x + MARGIN,
+		y:      g.layout.menu.y + g.layout.menu.height*0.1 + float32(index)*lineHeight,
+		width:  g.layout.menu.width - MARGIN*2,
+		height: lineHeight,
+	}
+}
+
+func (g *game) UpdateSettings() {
+	entries := g.SettingsEntries()
+
+	x, y := ebiten.CursorPosition()
+	for i := range entries {
+		if g.menuEntryArea(i, len(entries)).hit(float32(x), float32(y)) {
+			ebiten.SetCursorShape(ebiten.CursorShapePointer)
+			g.menuSelected = i
+			if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+				entries[i].change(1)
+			}
+		}
+	}
+
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowUp):
+		g.menuSelected = (g.menuSelected - 1 + len(entries)) % len(entries)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowDown):
+		g.menuSelected = (g.menuSelected + 1) % len(entries)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft):
+		entries[g.menuSelected].change(-1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyArrowRight), inpututil.IsKeyJustPressed(ebiten.KeyEnter):
+		entries[g.menuSelected].change(1)
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		g.CloseSettings()
+	}
+}
+
+func (g game) DrawSettings(screen *ebiten.Image) {
+	entries := g.SettingsEntries()
+	for i, entry := range entries {
+		area := g.menuEntryArea(i, len(entries))
+		if i == g.menuSelected {
+			vector.DrawFilledRect(screen, area.x, area.y, area.width, area.height, gray, false)
+		}
+		style := defaultTextStyle()
+		style.size *= g.menuLineScale(len(entries))
+		style.fit = area.width
+		g.DrawText(screen, entry.label(), area.x+area.width/2, area.y+area.height/2, style)
+	}
+}
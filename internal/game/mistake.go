@@ -0,0 +1,110 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+// mistake tells why a pick was wrong, they are stored in the stats so they
+// are strings
+type mistake string
+
+const (
+	NoMistake                mistake = ""
+	NoPickMistake            mistake = "no-pick"
+	ShapeInsteadOfGapMistake mistake = "shape-instead-of-gap"
+	NoFlipMistake            mistake = "no-flip"
+	SingleAxisFlipMistake    mistake = "single-axis-flip"
+	WrongSymbolMistake       mistake = "wrong-symbol"
+	WrongColumnMistake       mistake = "wrong-column"
+	UnknownMistake           mistake = "unknown"
+	SharedTileMistake        mistake = "shared-tile"
+	BlockedTetherMistake     mistake = "blocked-tether"
+)
+
+var mistakes = []mistake{
+	NoPickMistake,
+	ShapeInsteadOfGapMistake,
+	NoFlipMistake,
+	SingleAxisFlipMistake,
+	WrongSymbolMistake,
+	WrongColumnMistake,
+	UnknownMistake,
+	SharedTileMistake,
+	BlockedTetherMistake,
+}
+
+var mistakeMessages = map[mistake]message{
+	NoPickMistake:            NoPickMistakeMessage,
+	ShapeInsteadOfGapMistake: ShapeInsteadOfGapMistakeMessage,
+	NoFlipMistake:            NoFlipMistakeMessage,
+	SingleAxisFlipMistake:    SingleAxisFlipMistakeMessage,
+	WrongSymbolMistake:       WrongSymbolMistakeMessage,
+	WrongColumnMistake:       WrongColumnMistakeMessage,
+	UnknownMistake:           UnknownMistakeMessage,
+	SharedTileMistake:        SharedTileMistakeMessage,
+	BlockedTetherMistake:     BlockedTetherMistakeMessage,
+}
+
+func otherSymbol(symbol TileState) TileState {
+	if symbol == AlphaTile {
+		return BetaTile
+	}
+	return AlphaTile
+}
+
+// classifyMistake finds why a pick is wrong by resolving again the board, the
+// one before Panta Rhei, under each of the usual wrong assumptions and checking
+// if any of them gives the picked tile
+func classifyMistake(s strategy, original boardStates, symbol TileState, column int, markerColumns [NUM_MARKERS]int, picked bool, pick BoardPosition) mistake {
+	if !picked {
+		return NoPickMistake
+	}
+
+	flipped := original.flipped()
+	correct := s.Resolve(flipped, symbol, column)
+	if correct.found && correct.spot == pick {
+		return NoMistake
+	}
+
+	matches := func(res resolution) bool {
+		return res.found && res.spot == pick
+	}
+
+	switch {
+	case correct.found && (pick == correct.symbol || pick == correct.center):
+		return ShapeInsteadOfGapMistake
+	case matches(s.Resolve(original, symbol, column)):
+		return NoFlipMistake
+	case matches(s.Resolve(original.flippedAxes(true, false), symbol, column)),
+		matches(s.Resolve(original.flippedAxes(false, true), symbol, column)):
+		return SingleAxisFlipMistake
+	case matches(s.Resolve(flipped, otherSymbol(symbol), column)):
+		return WrongSymbolMistake
+	}
+
+	for _, c := range markerColumns {
+		if c != column && matches(s.Resolve(flipped, symbol, c)) {
+			return WrongColumnMistake
+		}
+	}
+
+	return UnknownMistake
+}
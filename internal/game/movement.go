@@ -0,0 +1,175 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/juan-medina/classical-concepts-2-trainer/internal/shapes"
+)
+
+const (
+	// character speed in tile radius units per second, about a column a second
+	MOVE_SPEED = 3
+	// gamepad sticks below this are considered centered
+	STICK_DEAD_ZONE = 0.2
+	// size of the character in tile radius units
+	CHARACTER_SIZE = 0.6
+)
+
+// character is the player in movement mode, its position is in tile radius
+// units from the center of the first tile so it does not depend on the screen
+type character struct {
+	x, y   float32
+	facing float32
+}
+
+// characterBounds returns how far the character can walk, the board and half
+// a tile around it
+func (g game) characterBounds() (minX, minY, maxX, maxY float32) {
+	return -TILE_FOOTPRINT / 2, -TILE_FOOTPRINT / 2,
+		float32(g.cols-1)*TILE_STEP_X + TILE_FOOTPRINT/2, float32(g.rows-1)*TILE_STEP_Y + TILE_FOOTPRINT/2
+}
+
+// startCharacter places the character where the settings say
+func (g *game) startCharacter() {
+	minX, minY, maxX, maxY := g.characterBounds()
+	switch g.settings.Start {
+	case MarkerStart:
+		// on the edge of the board, under our marker
+		g.character = character{x: float32(g.objectiveColumn()) * TILE_STEP_X, y: minY}
+	case RandomStart:
+		g.character = character{
+			x: minX + rand.Float32()*(maxX-minX),
+			y: minY + rand.Float32()*(maxY-minY),
+		}
+	default:
+		g.character = character{x: (minX + maxX) / 2, y: (minY + maxY) / 2}
+	}
+	// looking to the board
+	g.character.facing = 90
+}
+
+// movementInput returns where the player wants to go, from the keys or the
+// gamepads, with a length up to 1
+func (g *game) movementInput() (float32, float32) {
+	var dx, dy float32
+	if ebiten.IsKeyPressed(ebiten.KeyA) || ebiten.IsKeyPressed(ebiten.KeyArrowLeft) {
+		dx--
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyD) || ebiten.IsKeyPressed(ebiten.KeyArrowRight) {
+		dx++
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyW) || ebiten.IsKeyPressed(ebiten.KeyArrowUp) {
+		dy--
+	}
+	if ebiten.IsKeyPressed(ebiten.KeyS) || ebiten.IsKeyPressed(ebiten.KeyArrowDown) {
+		dy++
+	}
+
+	g.gamepads = ebiten.AppendGamepadIDs(g.gamepads[:0])
+	for _, id := range g.gamepads {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		sx := float32(ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickHorizontal))
+		sy := float32(ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxisLeftStickVertical))
+		if float32(math.Hypot(float64(sx), float64(sy))) > STICK_DEAD_ZONE {
+			dx += sx
+			dy += sy
+		}
+		if ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftLeft) {
+			dx--
+		}
+		if ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftRight) {
+			dx++
+		}
+		if ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftTop) {
+			dy--
+		}
+		if ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonLeftBottom) {
+			dy++
+		}
+	}
+
+	// going diagonal is not faster
+	length := float32(math.Hypot(float64(dx), float64(dy)))
+	if length > 1 {
+		dx, dy = dx/length, dy/length
+	}
+	return dx, dy
+}
+
+func (g *game) UpdateCharacter() {
+	dx, dy := g.movementInput()
+	if dx == 0 && dy == 0 {
+		return
+	}
+
+	step := MOVE_SPEED * float32(g.clock.Elapsed())
+	minX, minY, maxX, maxY := g.characterBounds()
+	g.character.x = min(max(g.character.x+dx*step, minX), maxX)
+	g.character.y = min(max(g.character.y+dy*step, minY), maxY)
+	g.character.facing = float32(math.Atan2(float64(dy), float64(dx)) * 180 / math.Pi)
+}
+
+// characterScreen returns where the character is on the screen
+func (g game) characterScreen() (float32, float32) {
+	return g.layout.boardX + g.character.x*g.layout.tileRadius, g.layout.boardY + g.character.y*g.layout.tileRadius
+}
+
+// CharacterTile returns the tile the character is standing in, if any
+func (g game) CharacterTile() (bool, BoardPosition) {
+	return g.tileAtUnits(g.character.x, g.character.y)
+}
+
+// DrawCharacter draws the character as a glowing circle with a triangle that
+// points where it goes
+func (g game) DrawCharacter(screen *ebiten.Image) {
+	x, y := g.characterScreen()
+	radius := g.layout.tileRadius * CHARACTER_SIZE
+	// drawn facing right and turned to where it faces
+	key := spriteKey{kind: CharacterSprite, size: radius}
+	drawSprite(screen, g.sprites.Get(key, radius*1.5+2, func(img *ebiten.Image, x, y float32) {
+		shapes.Circle(x, y, radius).Draw(img, shapes.Style{
+			Fill:        white,
+			FillEdge:    lightGray,
+			Stroke:      black,
+			StrokeWidth: radius / 6,
+			Glow:        white,
+			GlowWidth:   radius / 2,
+			AntiAlias:   true,
+		})
+		shapes.Polygon(x+radius*0.55, y, radius/3, 3, 0).Draw(img, shapes.Style{Fill: black, AntiAlias: true})
+	}), x, y, 1, g.character.facing)
+}
+
+func (g game) DrawMovementHelp(screen *ebiten.Image) {
+	style := defaultTextStyle()
+	style.size = CAPTION_FONT_SIZE
+	style.wrap = g.layout.caption.width
+	g.DrawText(screen, g.T(MovementHelpMessage), g.layout.statusX, g.layout.statusY, style)
+}
@@ -0,0 +1,125 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import "image/color"
+
+type palette struct {
+	name   string
+	label  message
+	alpha  color.RGBA64
+	beta   color.RGBA64
+	center color.RGBA64
+	// colors of the A, B, C and D markers
+	markers [4]color.RGBA64
+	// colors used to tell the objective column
+	columns [4]color.RGBA64
+	win     color.RGBA64
+	lose    color.RGBA64
+	// the cast bar, it needs to be apart from lose as it turns to it at the end
+	timeBar color.RGBA64
+	tether  color.RGBA64
+}
+
+func rgb(r, g, b uint8) color.RGBA64 {
+	return color.RGBA64{uint16(r) * 0x101, uint16(g) * 0x101, uint16(b) * 0x101, 0xFFFF}
+}
+
+// palettes for color vision deficiencies are built from the Okabe-Ito set,
+// picking per deficiency the colors that stay apart
+var palettes = []palette{
+	{
+		name:    "Default",
+		label:   DefaultPaletteMessage,
+		alpha:   red,
+		beta:    yellow,
+		center:  blue,
+		markers: [4]color.RGBA64{red, yellow, blue, purple},
+		columns: [4]color.RGBA64{red, green, blue, purple},
+		win:     green,
+		lose:    red,
+		timeBar: rgb(0xE8, 0xC0, 0x50),
+		tether:  darkPurple,
+	},
+	{
+		name:    "Deuteranopia",
+		label:   DeuteranopiaMessage,
+		alpha:   rgb(0xD5, 0x5E, 0x00),
+		beta:    rgb(0xF0, 0xE4, 0x42),
+		center:  rgb(0x00, 0x72, 0xB2),
+		markers: [4]color.RGBA64{rgb(0xD5, 0x5E, 0x00), rgb(0xF0, 0xE4, 0x42), rgb(0x56, 0xB4, 0xE9), rgb(0xCC, 0x79, 0xA7)},
+		columns: [4]color.RGBA64{rgb(0xD5, 0x5E, 0x00), rgb(0xF0, 0xE4, 0x42), rgb(0x56, 0xB4, 0xE9), rgb(0xCC, 0x79, 0xA7)},
+		win:     rgb(0x56, 0xB4, 0xE9),
+		lose:    rgb(0xE6, 0x9F, 0x00),
+		timeBar: rgb(0xF0, 0xE4, 0x42),
+		tether:  rgb(0xCC, 0x79, 0xA7),
+	},
+	{
+		name:    "Protanopia",
+		label:   ProtanopiaMessage,
+		alpha:   rgb(0xE6, 0x9F, 0x00),
+		beta:    rgb(0xF0, 0xE4, 0x42),
+		center:  rgb(0x00, 0x72, 0xB2),
+		markers: [4]color.RGBA64{rgb(0xE6, 0x9F, 0x00), rgb(0xF0, 0xE4, 0x42), rgb(0x56, 0xB4, 0xE9), rgb(0xFF, 0xFF, 0xFF)},
+		columns: [4]color.RGBA64{rgb(0xE6, 0x9F, 0x00), rgb(0xF0, 0xE4, 0x42), rgb(0x56, 0xB4, 0xE9), rgb(0xFF, 0xFF, 0xFF)},
+		win:     rgb(0x56, 0xB4, 0xE9),
+		lose:    rgb(0xE6, 0x9F, 0x00),
+		timeBar: rgb(0xF0, 0xE4, 0x42),
+		tether:  rgb(0x56, 0xB4, 0xE9),
+	},
+	{
+		name:    "Tritanopia",
+		label:   TritanopiaMessage,
+		alpha:   rgb(0xDC, 0x32, 0x20),
+		beta:    rgb(0xFF, 0x9E, 0xC8),
+		center:  rgb(0x00, 0x9E, 0x9E),
+		markers: [4]color.RGBA64{rgb(0xDC, 0x32, 0x20), rgb(0xFF, 0x9E, 0xC8), rgb(0x00, 0x9E, 0x9E), rgb(0xFF, 0xFF, 0xFF)},
+		columns: [4]color.RGBA64{rgb(0xDC, 0x32, 0x20), rgb(0xFF, 0x9E, 0xC8), rgb(0x00, 0x9E, 0x9E), rgb(0xFF, 0xFF, 0xFF)},
+		win:     rgb(0x00, 0x9E, 0x9E),
+		lose:    rgb(0xDC, 0x32, 0x20),
+		timeBar: rgb(0x00, 0x9E, 0x9E),
+		tether:  rgb(0xFF, 0x9E, 0xC8),
+	},
+	{
+		name:    "High Contrast",
+		label:   HighContrastMessage,
+		alpha:   rgb(0xFF, 0xFF, 0x00),
+		beta:    rgb(0x00, 0xFF, 0xFF),
+		center:  rgb(0xFF, 0x00, 0xFF),
+		markers: [4]color.RGBA64{rgb(0xFF, 0xFF, 0x00), rgb(0x00, 0xFF, 0xFF), rgb(0xFF, 0x00, 0xFF), rgb(0xFF, 0xFF, 0xFF)},
+		columns: [4]color.RGBA64{rgb(0xFF, 0xFF, 0x00), rgb(0x00, 0xFF, 0xFF), rgb(0xFF, 0x00, 0xFF), rgb(0xFF, 0xFF, 0xFF)},
+		win:     rgb(0x00, 0xFF, 0xFF),
+		lose:    rgb(0xFF, 0xFF, 0x00),
+		timeBar: rgb(0xFF, 0xFF, 0xFF),
+		tether:  rgb(0xFF, 0xFF, 0xFF),
+	},
+}
+
+func findPalette(name string) palette {
+	for _, p := range palettes {
+		if p.name == name {
+			return p
+		}
+	}
+	return palettes[0]
+}
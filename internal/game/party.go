@@ -0,0 +1,185 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/juan-medina/classical-concepts-2-trainer/internal/shapes"
+)
+
+const (
+	// the party is 8 players, one per symbol and marker, we are one of them
+	PARTY_SIZE = 8
+	// party mistakes in percent and reaction time in seconds, with their steps
+	// and limits to change them
+	PARTY_ERRORS      = 10
+	PARTY_ERRORS_STEP = 10
+	MAX_PARTY_ERRORS  = 50
+	PARTY_DELAY       = 1
+	PARTY_DELAY_STEP  = 0.5
+	MAX_PARTY_DELAY   = 3
+	// size of the party members in tile radius units
+	PARTY_MEMBER_SIZE = 0.4
+)
+
+// partyMember is a simulated player, it waits its reaction time and then walks
+// to where it thinks its spot is, positions are in tile radius units as the
+// character ones
+type partyMember struct {
+	symbol           TileState
+	column           int
+	x, y             float32
+	targetX, targetY float32
+	// seconds of game time when it starts to move
+	moveAt float64
+	// if it is going to a wrong spot
+	wrong bool
+	// if the strategy gave it no spot, it stays where it started and does not
+	// take any tile
+	lost bool
+}
+
+// boardUnits returns the center of a tile in tile radius units
+func boardUnits(position BoardPosition) (float32, float32) {
+	return float32(position.column) * TILE_STEP_X, float32(position.row) * TILE_STEP_Y
+}
+
+// wrongSpot returns where a player ends making one of the usual mistakes, if
+// any of them takes it to a different spot
+func wrongSpot(s strategy, original boardStates, symbol TileState, column int, correct BoardPosition) (bool, BoardPosition) {
+	candidates := []resolution{
+		s.Resolve(original, symbol, column),
+		s.Resolve(original.flippedAxes(true, false), symbol, column),
+		s.Resolve(original.flippedAxes(false, true), symbol, column),
+		s.Resolve(original.flipped(), otherSymbol(symbol), column),
+	}
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	for _, c := range candidates {
+		if c.found && c.spot != correct {
+			return true, c.spot
+		}
+	}
+	return false, correct
+}
+
+// StartParty places the rest of the party stacked in the middle of the board
+// and sends each one to its spot
+func (g *game) StartParty() {
+	g.party = g.party[:0]
+	if !g.settings.Party {
+		return
+	}
+
+	original := g.States()
+	flipped := original.flipped()
+	minX, minY, maxX, maxY := g.characterBounds()
+	centerX, centerY := (minX+maxX)/2, (minY+maxY)/2
+	now := g.clock.Now()
+
+	for _, symbol := range []TileState{AlphaTile, BetaTile} {
+		for column := 0; column < NUM_MARKERS; column++ {
+			if symbol == g.symbolObjective && column == g.columnObjective {
+				continue
+			}
+			boardColumn := g.definition.markerColumns[column]
+			member := partyMember{symbol: symbol, column: column}
+
+			angle := 2 * math.Pi * float64(len(g.party)) / (PARTY_SIZE - 1)
+			member.x = centerX + float32(math.Cos(angle))
+			member.y = centerY + float32(math.Sin(angle))
+			member.targetX, member.targetY = member.x, member.y
+
+			res := g.strategy.Resolve(flipped, symbol, boardColumn)
+			if res.found {
+				spot := res.spot
+				if rand.Intn(100) < g.settings.PartyErrors {
+					member.wrong, spot = wrongSpot(g.strategy, original, symbol, boardColumn, res.spot)
+				}
+				member.targetX, member.targetY = boardUnits(spot)
+			} else {
+				member.lost = true
+			}
+			// reactions go from half to one and a half times the setting
+			member.moveAt = now + g.settings.PartyDelay*(0.5+rand.Float64())
+
+			g.party = append(g.party, member)
+		}
+	}
+}
+
+func (g *game) UpdateParty() {
+	now := g.clock.Now()
+	step := MOVE_SPEED * float32(g.clock.Elapsed())
+	for i := range g.party {
+		m := &g.party[i]
+		if now < m.moveAt {
+			continue
+		}
+		dx, dy := m.targetX-m.x, m.targetY-m.y
+		distance := float32(math.Hypot(float64(dx), float64(dy)))
+		if distance <= step {
+			m.x, m.y = m.targetX, m.targetY
+			continue
+		}
+		m.x += dx / distance * step
+		m.y += dy / distance * step
+	}
+}
+
+// DrawParty draws each party member as a circle of its marker color with its
+// symbol inside
+func (g game) DrawParty(screen *ebiten.Image) {
+	radius := g.layout.tileRadius * PARTY_MEMBER_SIZE
+	for _, m := range g.party {
+		x := g.layout.boardX + m.x*g.layout.tileRadius
+		y := g.layout.boardY + m.y*g.layout.tileRadius
+		g.DrawMember(screen, x, y, radius, m.symbol, m.column, black)
+	}
+}
+
+// DrawMember draws a party member, in shape only mode its waymark is next to
+// it as the color is not enough to tell the column
+func (g game) DrawMember(screen *ebiten.Image, x, y, radius float32, symbol TileState, column int, stroke color.Color) {
+	key := spriteKey{kind: MemberSprite, size: radius, fill: g.columnColor(column), stroke: rgba64(stroke), symbol: symbol}
+	drawSprite(screen, g.sprites.Get(key, radius*1.1+2, func(img *ebiten.Image, x, y float32) {
+		shapes.Circle(x, y, radius).Draw(img, shapes.Style{
+			Fill:        key.fill,
+			Stroke:      key.stroke,
+			StrokeWidth: radius / 6,
+			AntiAlias:   true,
+		})
+		batch := &shapes.Batch{}
+		g.DrawSymbol(batch, x, y, radius/2, 0, symbol)
+		batch.Flush(img)
+	}), x, y, 1, 0)
+	if g.settings.ShapeOnly {
+		g.DrawWaymark(screen, x+radius, y-radius, radius/2, column)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// pauseKeyPressed tells if any of the keys that pause and resume the round was
+// just pressed
+func pauseKeyPressed() bool {
+	return inpututil.IsKeyJustPressed(ebiten.KeyP) || inpututil.IsKeyJustPressed(ebiten.KeyEscape)
+}
+
+// UpdatePauseRequest pauses the round with the pause keys or when the window,
+// or the browser tab, loses the focus
+func (g *game) UpdatePauseRequest() bool {
+	if pauseKeyPressed() || !ebiten.IsFocused() {
+		g.Pause()
+		return true
+	}
+	return false
+}
+
+func (g *game) Pause() {
+	g.clock.Pause()
+	g.RemoveTileWithState(MouseOverTile)
+	g.state = PausedState
+}
+
+func (g *game) Resume() {
+	g.clock.Resume()
+	g.state = PlayingState
+}
+
+func (g *game) Restart() {
+	g.clock.Resume()
+	g.Reset()
+}
+
+func (g *game) Quit() {
+	g.clock.Resume()
+	g.Standby()
+}
+
+func (g *game) UpdatePause() {
+	if pauseKeyPressed() {
+		g.Resume()
+	}
+}
+
+// DrawPause only tells the round is paused, the board is hidden so it can not
+// be studied while the time is stopped
+func (g game) DrawPause(screen *ebiten.Image) {
+	menu := g.layout.menu
+	g.DrawText(screen, g.T(PausedMessage), menu.x+menu.width/2, menu.y+menu.height/2, defaultTextStyle())
+}
@@ -0,0 +1,175 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+// boardStates is a snapshot of the state of every tile, so the board can be
+// flipped and resolved without touching the one being played
+type boardStates [][]TileState
+
+func newBoardStates(rows, cols int) boardStates {
+	b := make(boardStates, rows)
+	for r := range b {
+		b[r] = make([]TileState, cols)
+	}
+	return b
+}
+
+type resolution struct {
+	found bool
+	// the hexagon in the objective column
+	center BoardPosition
+	// the objective symbol two tiles away from the hexagon
+	symbol BoardPosition
+	// the tile between them, where the player needs to be
+	spot BoardPosition
+}
+
+func isSymbol(state TileState) bool {
+	return state == AlphaTile || state == BetaTile || state == CenterTile
+}
+
+func (g game) States() boardStates {
+	states := newBoardStates(g.rows, g.cols)
+	for r := 0; r < g.rows; r++ {
+		for c := 0; c < g.cols; c++ {
+			states[r][c] = g.board[r][c].state
+		}
+	}
+	return states
+}
+
+func (b boardStates) rows() int {
+	return len(b)
+}
+
+func (b boardStates) cols() int {
+	return len(b[0])
+}
+
+// flipped returns the states after Panta Rhei, the symbols are mirrored in
+// both axes while the rest of tiles stay as they are
+func (b boardStates) flipped() boardStates {
+	return b.flippedAxes(true, true)
+}
+
+// flippedAxes mirrors the symbols only in the given axes
+func (b boardStates) flippedAxes(rows, columns bool) boardStates {
+	result := newBoardStates(b.rows(), b.cols())
+	for r := 0; r < b.rows(); r++ {
+		copy(result[r], b[r])
+	}
+	for r := 0; r < b.rows(); r++ {
+		for c := 0; c < b.cols(); c++ {
+			if isSymbol(b[r][c]) {
+				result[r][c] = EmptyTile
+			}
+		}
+	}
+	for r := 0; r < b.rows(); r++ {
+		for c := 0; c < b.cols(); c++ {
+			if isSymbol(b[r][c]) {
+				nr, nc := r, c
+				if rows {
+					nr = b.rows() - 1 - r
+				}
+				if columns {
+					nc = b.cols() - 1 - c
+				}
+				result[nr][nc] = b[r][c]
+			}
+		}
+	}
+	return result
+}
+
+// resolve finds where a player with a symbol and a marker over a board column
+// needs to be
+func (b boardStates) resolve(symbol TileState, objectiveColumn int) resolution {
+	objectiveRow := 0
+
+	for r := 0; r < b.rows(); r++ {
+		if b[r][objectiveColumn] == CenterTile {
+			objectiveRow = r
+			break
+		}
+	}
+
+	res := resolution{center: BoardPosition{row: objectiveRow, column: objectiveColumn}}
+
+	posible := b.tilesAround(objectiveRow, objectiveColumn, symbol)
+	possibles := len(posible)
+	if possibles > 0 {
+		if possibles == 1 {
+			res.found = true
+			res.symbol = posible[0]
+		} else {
+			for _, p := range posible {
+				if len(b.tilesAround(p.row, p.column, CenterTile)) == 1 {
+					res.found = true
+					res.symbol = p
+					break
+				}
+			}
+		}
+	}
+
+	if res.found {
+		res.spot = BoardPosition{
+			row:    (res.center.row + res.symbol.row) / 2,
+			column: (res.center.column + res.symbol.column) / 2,
+		}
+	}
+
+	return res
+}
+
+func (b boardStates) tilesAround(row, column int, state TileState) []BoardPosition {
+	result := []BoardPosition{}
+
+	// look 2 up
+	if row > 1 {
+		if b[row-2][column] == state {
+			result = append(result, BoardPosition{row: row - 2, column: column})
+		}
+	}
+	// look 2 down
+	if row < b.rows()-2 {
+		if b[row+2][column] == state {
+			result = append(result, BoardPosition{row: row + 2, column: column})
+		}
+	}
+	// look 2 left
+	if column > 1 {
+		if b[row][column-2] == state {
+			result = append(result, BoardPosition{row: row, column: column - 2})
+		}
+	}
+	// look 2 right
+	if column < b.cols()-2 {
+		if b[row][column+2] == state {
+			result = append(result, BoardPosition{row: row, column: column + 2})
+		}
+	}
+
+	return result
+}
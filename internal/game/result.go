@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+
+	"github.com/juan-medina/classical-concepts-2-trainer/internal/shapes"
+)
+
+// pickOffset describes, with arrows, how many tiles away the correct tile is
+// from the pick, as "↑1 →2"
+func pickOffset(pick, correct BoardPosition) string {
+	var parts []string
+	rows := correct.row - pick.row
+	columns := correct.column - pick.column
+	switch {
+	case rows < 0:
+		parts = append(parts, fmt.Sprintf("↑%d", -rows))
+	case rows > 0:
+		parts = append(parts, fmt.Sprintf("↓%d", rows))
+	}
+	switch {
+	case columns < 0:
+		parts = append(parts, fmt.Sprintf("←%d", -columns))
+	case columns > 0:
+		parts = append(parts, fmt.Sprintf("→%d", columns))
+	}
+	return strings.Join(parts, " ")
+}
+
+// DrawPickResult marks the correct tile with a circle and, if it was missed,
+// the pick with a square and an arrow from it to the correct tile, if it was
+// right but shared with someone it crosses it out instead
+func (g game) DrawPickResult(screen *ebiten.Image) {
+	if !g.resolution.found {
+		return
+	}
+
+	radius := g.layout.tileRadius
+	stroke := radius / 8
+	correct := g.board[g.resolution.spot.row][g.resolution.spot.column]
+	vector.StrokeCircle(screen, correct.x, correct.y, radius*1.7, stroke, g.palette.win, true)
+
+	if !g.playerPicked || g.win {
+		return
+	}
+	if g.playerPick == g.resolution.spot {
+		g.DrawSharedTile(screen, correct.x, correct.y)
+		return
+	}
+
+	pick := g.board[g.playerPick.row][g.playerPick.column]
+	size := radius * 3
+	vector.StrokeRect(screen, pick.x-size/2, pick.y-size/2, size, size, stroke, g.palette.lose, true)
+
+	// the arrow goes from the edge of the pick square to the edge of the circle
+	dx, dy := correct.x-pick.x, correct.y-pick.y
+	length := float32(math.Hypot(float64(dx), float64(dy)))
+	if length == 0 {
+		return
+	}
+	ux, uy := dx/length, dy/length
+	fromX, fromY := pick.x+ux*radius, pick.y+uy*radius
+	toX, toY := correct.x-ux*radius*1.7, correct.y-uy*radius*1.7
+	shapes.Arrow(fromX, fromY, toX, toY, stroke, radius/2).Draw(screen, shapes.Style{Fill: g.palette.lose, AntiAlias: true})
+
+	style := defaultTextStyle()
+	style.size = SMALL_FONT_SIZE
+	style.outline = 4
+	style.color = g.palette.lose
+	g.DrawText(screen, pickOffset(g.playerPick, g.resolution.spot), (pick.x+correct.x)/2, (pick.y+correct.y)/2-radius, style)
+}
+
+// DrawSharedTile crosses out a tile and puts around it the players that were
+// standing in it
+func (g game) DrawSharedTile(screen *ebiten.Image, x, y float32) {
+	radius := g.layout.tileRadius
+	stroke := radius / 8
+	size := radius * 0.9
+	vector.StrokeLine(screen, x-size, y-size, x+size, y+size, stroke, g.palette.lose, true)
+	vector.StrokeLine(screen, x-size, y+size, x+size, y-size, stroke, g.palette.lose, true)
+
+	var shared []collision
+	for _, c := range g.collisions {
+		if c.kind == SharedTileMistake {
+			shared = append(shared, c)
+		}
+	}
+	memberRadius := radius * PARTY_MEMBER_SIZE
+	for i, c := range shared {
+		angle := -math.Pi/2 + 2*math.Pi*float64(i)/float64(len(shared))
+		mx := x + float32(math.Cos(angle))*radius*1.7
+		my := y + float32(math.Sin(angle))*radius*1.7
+		g.DrawMember(screen, mx, my, memberRadius, c.symbol, c.column, g.palette.lose)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"encoding/json"
+	"log"
+)
+
+const (
+	SETTINGS_KEY = "settings"
+	// round speed in percent, and the step and limits to change it
+	NORMAL_SPEED = 100
+	SPEED_STEP   = 25
+	MIN_SPEED    = 50
+	MAX_SPEED    = 150
+)
+
+type gameMode string
+
+const (
+	NormalMode   gameMode = "normal"
+	DrillMode    gameMode = "drill"
+	MovementMode gameMode = "movement"
+)
+
+var modes = []gameMode{NormalMode, DrillMode, MovementMode}
+
+var modeMessages = map[gameMode]message{
+	NormalMode:   NormalModeMessage,
+	DrillMode:    DrillModeMessage,
+	MovementMode: MovementModeMessage,
+}
+
+// where the character starts in movement mode
+type startPosition string
+
+const (
+	CenterStart startPosition = "center"
+	MarkerStart startPosition = "marker"
+	RandomStart startPosition = "random"
+)
+
+var startPositions = []startPosition{CenterStart, MarkerStart, RandomStart}
+
+var startMessages = map[startPosition]message{
+	CenterStart: CenterStartMessage,
+	MarkerStart: MarkerStartMessage,
+	RandomStart: RandomStartMessage,
+}
+
+type settings struct {
+	Palette   string   `json:"palette"`
+	ShapeOnly bool     `json:"shapeOnly"`
+	Language  string   `json:"language"`
+	Volume    int      `json:"volume"`
+	Mute      bool     `json:"mute"`
+	Hints     bool     `json:"hints"`
+	Mode      gameMode `json:"mode"`
+	// seconds the Panta Rhei animation takes, 0 to skip it
+	FlipTime float64 `json:"flipTime"`
+	// percentage of the normal speed the round time goes at
+	Speed int           `json:"speed"`
+	Start startPosition `json:"start"`
+	// the simulated party, how often they go wrong, in percent, and how long
+	// they take to react, in seconds
+	Party       bool              `json:"party"`
+	PartyErrors int               `json:"partyErrors"`
+	PartyDelay  float64           `json:"partyDelay"`
+	Strategy    string            `json:"strategy"`
+	Waymarks    string            `json:"waymarks"`
+	Assignment  assignmentDisplay `json:"assignment"`
+	// chain Classical Concepts and Panta Rhei casts with their real timings
+	CastChain bool `json:"castChain"`
+}
+
+func defaultSettings() settings {
+	return settings{
+		Palette:     palettes[0].name,
+		ShapeOnly:   false,
+		Language:    AUTO_LANGUAGE,
+		Volume:      MAX_VOLUME / 2,
+		Mute:        false,
+		Hints:       true,
+		Mode:        NormalMode,
+		FlipTime:    FLIP_TIME,
+		Speed:       NORMAL_SPEED,
+		Start:       CenterStart,
+		Party:       false,
+		PartyErrors: PARTY_ERRORS,
+		PartyDelay:  PARTY_DELAY,
+		Strategy:    DEFAULT_STRATEGY,
+		Waymarks:    AUTO_WAYMARKS,
+		Assignment:  TextAssignment,
+		CastChain:   false,
+	}
+}
+
+// loadSettings reads the stored settings, anything missing or unreadable keeps
+// its default value
+func loadSettings() settings {
+	s := defaultSettings()
+	data, err := readStorage(SETTINGS_KEY)
+	if err != nil {
+		return s
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		log.Printf("ignoring invalid settings: %v", err)
+		return defaultSettings()
+	}
+	return s
+}
+
+func (s settings) save() {
+	data, err := json.Marshal(s)
+	if err != nil {
+		log.Printf("can not encode settings: %v", err)
+		return
+	}
+	if err := writeStorage(SETTINGS_KEY, data); err != nil {
+		log.Printf("can not save settings: %v", err)
+	}
+}
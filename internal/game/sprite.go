@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+type spriteKind int
+
+const (
+	WaymarkSprite spriteKind = iota
+	MemberSprite
+	CharacterSprite
+	RingSprite
+)
+
+// spriteKey tells apart the sprites, anything that changes how one looks has
+// to be in it
+type spriteKey struct {
+	kind   spriteKind
+	size   float32
+	fill   color.RGBA64
+	stroke color.RGBA64
+	label  string
+	shape  waymarkShape
+	symbol TileState
+}
+
+// sprites keeps what is drawn every frame with shapes and text already drawn
+// in images, so each frame only copies them to the screen
+type sprites struct {
+	images map[spriteKey]*ebiten.Image
+}
+
+func newSprites() *sprites {
+	return &sprites{images: map[spriteKey]*ebiten.Image{}}
+}
+
+// Get returns the sprite of a key, the first time it calls draw with an image
+// that has extent pixels around its center
+func (s *sprites) Get(key spriteKey, extent float32, draw func(img *ebiten.Image, x, y float32)) *ebiten.Image {
+	if img, ok := s.images[key]; ok {
+		return img
+	}
+	side := int(math.Ceil(float64(extent * 2)))
+	img := ebiten.NewImage(side, side)
+	draw(img, float32(side)/2, float32(side)/2)
+	s.images[key] = img
+	return img
+}
+
+// Clear drops the sprites, as when the layout changes they no longer fit
+func (s *sprites) Clear() {
+	for key, img := range s.images {
+		img.Deallocate()
+		delete(s.images, key)
+	}
+}
+
+// drawSprite draws a sprite centered in a position, scaled and rotated, in
+// degrees, around its center
+func drawSprite(screen, img *ebiten.Image, x, y, scale, rotation float32) {
+	bounds := img.Bounds()
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	op.GeoM.Translate(-float64(bounds.Dx())/2, -float64(bounds.Dy())/2)
+	op.GeoM.Scale(float64(scale), float64(scale))
+	op.GeoM.Rotate(float64(rotation) * math.Pi / 180)
+	op.GeoM.Translate(float64(x), float64(y))
+	screen.DrawImage(img, op)
+}
+
+func rgba64(clr color.Color) color.RGBA64 {
+	r, g, b, a := clr.RGBA()
+	return color.RGBA64{uint16(r), uint16(g), uint16(b), uint16(a)}
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const STATS_KEY = "stats"
+
+type stats struct {
+	Rounds int `json:"rounds"`
+	Wins   int `json:"wins"`
+	Score  int `json:"score"`
+	Hints  int `json:"hints"`
+	// rounds of the pre-flip drill and how many had the first phase right
+	DrillRounds  int             `json:"drillRounds"`
+	DrillPreFlip int             `json:"drillPreFlip"`
+	Mistakes     map[mistake]int `json:"mistakes"`
+}
+
+func loadStats() stats {
+	s := stats{Mistakes: map[mistake]int{}}
+	data, err := readStorage(STATS_KEY)
+	if err != nil {
+		return s
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		log.Printf("ignoring invalid stats: %v", err)
+		return stats{Mistakes: map[mistake]int{}}
+	}
+	if s.Mistakes == nil {
+		s.Mistakes = map[mistake]int{}
+	}
+	return s
+}
+
+func (s stats) save() {
+	data, err := json.Marshal(s)
+	if err != nil {
+		log.Printf("can not encode stats: %v", err)
+		return
+	}
+	if err := writeStorage(STATS_KEY, data); err != nil {
+		log.Printf("can not save stats: %v", err)
+	}
+}
+
+func (s *stats) record(win bool, m mistake, score, hints int) {
+	s.Rounds++
+	s.Score += score
+	s.Hints += hints
+	if win {
+		s.Wins++
+	} else {
+		s.Mistakes[m]++
+	}
+	s.save()
+}
+
+func (s *stats) recordDrill(preFlipCorrect bool) {
+	s.DrillRounds++
+	if preFlipCorrect {
+		s.DrillPreFlip++
+	}
+}
+
+func (s stats) averageScore() int {
+	if s.Rounds == 0 {
+		return 0
+	}
+	return s.Score / s.Rounds
+}
+
+func (s stats) winRate() int {
+	if s.Rounds == 0 {
+		return 0
+	}
+	return s.Wins * 100 / s.Rounds
+}
+
+func (g *game) OpenStats() {
+	g.menuReturnState = g.state
+	g.state = StatsState
+}
+
+func (g *game) CloseStats() {
+	g.state = g.menuReturnState
+}
+
+func (g game) DrawStats(screen *ebiten.Image) {
+	lines := []string{
+		fmt.Sprintf(g.T(RoundsMessage), g.stats.Rounds),
+		fmt.Sprintf(g.T(WinsMessage), g.stats.Wins, g.stats.winRate()),
+		fmt.Sprintf(g.T(AverageScoreMessage), g.stats.averageScore()),
+		fmt.Sprintf(g.T(HintsUsedMessage), g.stats.Hints),
+	}
+	if g.stats.DrillRounds > 0 {
+		lines = append(lines, fmt.Sprintf(g.T(DrillStatsMessage), g.stats.DrillPreFlip, g.stats.DrillRounds))
+	}
+	for _, m := range mistakes {
+		if count := g.stats.Mistakes[m]; count > 0 {
+			lines = append(lines, fmt.Sprintf("%d × %s", count, g.T(mistakeMessages[m])))
+		}
+	}
+
+	style := defaultTextStyle()
+	style.size = SMALL_FONT_SIZE * g.menuLineScale(len(lines))
+	style.fit = g.layout.menu.width - MARGIN*2
+	for i, line := range lines {
+		area := g.menuEntryArea(i, len(lines))
+		g.DrawText(screen, line, area.x+area.width/2, area.y+area.height/2, style)
+	}
+}
@@ -0,0 +1,54 @@
+//go:build js
+
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"errors"
+	"syscall/js"
+)
+
+const STORAGE_PREFIX = "cc2t."
+
+// readStorage and writeStorage keep data in the browser local storage
+func readStorage(key string) ([]byte, error) {
+	storage := js.Global().Get("localStorage")
+	if !storage.Truthy() {
+		return nil, errors.New("local storage not available")
+	}
+	value := storage.Call("getItem", STORAGE_PREFIX+key)
+	if value.IsNull() {
+		return nil, errors.New("not found: " + key)
+	}
+	return []byte(value.String()), nil
+}
+
+func writeStorage(key string, data []byte) error {
+	storage := js.Global().Get("localStorage")
+	if !storage.Truthy() {
+		return errors.New("local storage not available")
+	}
+	storage.Call("setItem", STORAGE_PREFIX+key, string(data))
+	return nil
+}
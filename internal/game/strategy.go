@@ -0,0 +1,254 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+)
+
+const (
+	DEFAULT_STRATEGY = "default"
+	// where the strategy files are in the embedded resources
+	STRATEGIES_DIR = "embed/strategies"
+	// storage key of a strategy file the player can add
+	STRATEGY_KEY = "strategy"
+)
+
+// strategy is how a group resolves where a player with a symbol and a marker
+// column goes after Panta Rhei
+type strategy interface {
+	ID() string
+	// Label is the name of the strategy in a language
+	Label(language string) string
+	Resolve(states boardStates, symbol TileState, column int) resolution
+}
+
+var (
+	strategies  = map[string]strategy{}
+	strategyIDs []string
+)
+
+// registerStrategy adds a strategy, or replaces the one with the same id
+func registerStrategy(s strategy) {
+	if _, exists := strategies[s.ID()]; !exists {
+		strategyIDs = append(strategyIDs, s.ID())
+	}
+	strategies[s.ID()] = s
+}
+
+func findStrategy(id string) strategy {
+	if s, ok := strategies[id]; ok {
+		return s
+	}
+	return strategies[DEFAULT_STRATEGY]
+}
+
+// defaultStrategy is the usual rule: the hexagon in our column, our symbol two
+// tiles away from it and the spot in between
+type defaultStrategy struct{}
+
+func (defaultStrategy) ID() string {
+	return DEFAULT_STRATEGY
+}
+
+func (defaultStrategy) Label(language string) string {
+	return translate(language, DefaultStrategyMessage)
+}
+
+func (defaultStrategy) Resolve(states boardStates, symbol TileState, column int) resolution {
+	return states.resolve(symbol, column)
+}
+
+func init() {
+	registerStrategy(defaultStrategy{})
+}
+
+// direction to look for the symbol from the hexagon
+type direction string
+
+const (
+	UpDirection    direction = "up"
+	DownDirection  direction = "down"
+	LeftDirection  direction = "left"
+	RightDirection direction = "right"
+)
+
+var directionSteps = map[direction]BoardPosition{
+	UpDirection:    {row: -1},
+	DownDirection:  {row: 1},
+	LeftDirection:  {column: -1},
+	RightDirection: {column: 1},
+}
+
+// what a declarative strategy does when the symbol is in more than one
+// direction
+const (
+	// the symbol that has a single hexagon around, as the default strategy
+	LonelyAmbiguity = "lonely"
+	// the first one found, in the order of the directions
+	FirstAmbiguity = "first"
+)
+
+// strategyFile is what a json strategy file has
+type strategyFile struct {
+	ID     string            `json:"id"`
+	Labels map[string]string `json:"labels"`
+	// how many tiles away the symbol is from the hexagon
+	Distance   int         `json:"distance"`
+	Directions []direction `json:"directions"`
+	Ambiguous  string      `json:"ambiguous"`
+	// the waymarks the group uses, if they are not the ones of the board
+	Waymarks *[NUM_MARKERS]waymark `json:"waymarks"`
+}
+
+// declarativeStrategy is a strategy read from a json file
+type declarativeStrategy struct {
+	strategyFile
+}
+
+func parseStrategy(data []byte) (*declarativeStrategy, error) {
+	s := &declarativeStrategy{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	if s.strategyFile.ID == "" {
+		return nil, fmt.Errorf("strategy without id")
+	}
+	if s.Distance < 2 || s.Distance%2 != 0 {
+		return nil, fmt.Errorf("strategy %q: distance needs to be even, to have a tile in between", s.ID())
+	}
+	if len(s.Directions) == 0 {
+		return nil, fmt.Errorf("strategy %q: no directions", s.ID())
+	}
+	for _, d := range s.Directions {
+		if _, ok := directionSteps[d]; !ok {
+			return nil, fmt.Errorf("strategy %q: unknown direction %q", s.ID(), d)
+		}
+	}
+	if s.Ambiguous != LonelyAmbiguity && s.Ambiguous != FirstAmbiguity {
+		return nil, fmt.Errorf("strategy %q: unknown ambiguous rule %q", s.ID(), s.Ambiguous)
+	}
+	if s.Waymarks != nil {
+		if err := validWaymarks(*s.Waymarks); err != nil {
+			return nil, fmt.Errorf("strategy %q: %w", s.ID(), err)
+		}
+	}
+	return s, nil
+}
+
+func (s *declarativeStrategy) ID() string {
+	return s.strategyFile.ID
+}
+
+func (s *declarativeStrategy) Label(language string) string {
+	if label, ok := s.Labels[language]; ok {
+		return label
+	}
+	if label, ok := s.Labels[DEFAULT_LANGUAGE]; ok {
+		return label
+	}
+	return s.strategyFile.ID
+}
+
+// around returns the tiles with a state at the strategy distance, in the order
+// of its directions
+func (s *declarativeStrategy) around(states boardStates, row, column int, state TileState) []BoardPosition {
+	var result []BoardPosition
+	for _, d := range s.Directions {
+		step := directionSteps[d]
+		r, c := row+step.row*s.Distance, column+step.column*s.Distance
+		if r >= 0 && r < states.rows() && c >= 0 && c < states.cols() && states[r][c] == state {
+			result = append(result, BoardPosition{row: r, column: c})
+		}
+	}
+	return result
+}
+
+func (s *declarativeStrategy) Resolve(states boardStates, symbol TileState, column int) resolution {
+	res := resolution{center: BoardPosition{column: column}}
+	for r := 0; r < states.rows(); r++ {
+		if states[r][column] == CenterTile {
+			res.center.row = r
+			break
+		}
+	}
+
+	candidates := s.around(states, res.center.row, column, symbol)
+	switch {
+	case len(candidates) == 1, len(candidates) > 1 && s.Ambiguous == FirstAmbiguity:
+		res.found = true
+		res.symbol = candidates[0]
+	case len(candidates) > 1:
+		for _, p := range candidates {
+			if len(s.around(states, p.row, p.column, CenterTile)) == 1 {
+				res.found = true
+				res.symbol = p
+				break
+			}
+		}
+	}
+
+	if res.found {
+		res.spot = BoardPosition{
+			row:    (res.center.row + res.symbol.row) / 2,
+			column: (res.center.column + res.symbol.column) / 2,
+		}
+	}
+	return res
+}
+
+// loadStrategies registers the strategies embedded with the game, that need
+// to be right, and the one the player may have stored, that is skipped if not
+func loadStrategies(er embed.FS) {
+	entries, err := fs.ReadDir(er, STRATEGIES_DIR)
+	if err != nil {
+		panic(err)
+	}
+	for _, entry := range entries {
+		data, err := er.ReadFile(path.Join(STRATEGIES_DIR, entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		s, err := parseStrategy(data)
+		if err != nil {
+			panic(err)
+		}
+		registerStrategy(s)
+	}
+
+	data, err := readStorage(STRATEGY_KEY)
+	if err != nil {
+		return
+	}
+	s, err := parseStrategy(data)
+	if err != nil {
+		log.Printf("ignoring invalid strategy: %v", err)
+		return
+	}
+	registerStrategy(s)
+}
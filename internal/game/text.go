@@ -0,0 +1,213 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"bytes"
+	"image/color"
+	"io/fs"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// font sizes in pixels for the 1920x1080 design, scaled with the layout
+const (
+	DEFAULT_FONT_SIZE = 88
+	SMALL_FONT_SIZE   = 62
+	CAPTION_FONT_SIZE = 48
+	LINE_SPACING      = 1.2
+)
+
+type textStyle struct {
+	size  float32
+	color color.Color
+	// horizontal and vertical alignment relative to the drawing point
+	align         text.Align
+	verticalAlign text.Align
+	// wrap the text in lines not wider than this, 0 to not wrap
+	wrap float32
+	// shrink the text to not be wider than this, 0 to not shrink
+	fit          float32
+	outline      float32
+	outlineColor color.Color
+	shadow       float32
+	shadowColor  color.Color
+}
+
+func defaultTextStyle() textStyle {
+	return textStyle{
+		size:          DEFAULT_FONT_SIZE,
+		color:         white,
+		align:         text.AlignCenter,
+		verticalAlign: text.AlignCenter,
+		outlineColor:  black,
+		shadowColor:   black,
+	}
+}
+
+// fonts keeps the font sources and a face for each size in use, the font
+// glyphs are cached by text/v2 per face
+type fonts struct {
+	sources []*text.GoTextFaceSource
+	faces   map[float32]text.Face
+}
+
+// loadFonts reads the fonts in order of preference, any glyph missing in a
+// font is taken from the following ones, as the japanese characters
+func loadFonts(er fs.FS, files ...string) *fonts {
+	f := &fonts{faces: map[float32]text.Face{}}
+	for _, file := range files {
+		fontBytes, err := fs.ReadFile(er, file)
+		if err != nil {
+			panic(err)
+		}
+		source, err := text.NewGoTextFaceSource(bytes.NewReader(fontBytes))
+		if err != nil {
+			panic(err)
+		}
+		f.sources = append(f.sources, source)
+	}
+	return f
+}
+
+func (f *fonts) face(size float32) text.Face {
+	// avoid a new face, and its glyph cache, for tiny size changes
+	size = float32(math.Round(float64(size)))
+	if face, ok := f.faces[size]; ok {
+		return face
+	}
+
+	var faces []text.Face
+	for _, source := range f.sources {
+		faces = append(faces, &text.GoTextFace{Source: source, Size: float64(size)})
+	}
+	face, err := text.NewMultiFace(faces...)
+	if err != nil {
+		panic(err)
+	}
+	f.faces[size] = face
+	return face
+}
+
+// wrapText splits a text in lines that fit in a width, words that do not fit
+// alone, or text without spaces as in japanese, are split by characters
+func wrapText(str string, face text.Face, width float64) string {
+	var lines []string
+	for _, paragraph := range strings.Split(str, "\n") {
+		line := ""
+		for _, word := range splitWords(paragraph) {
+			candidate := line + word
+			if line != "" && text.Advance(candidate, face) > width {
+				lines = append(lines, strings.TrimRightFunc(line, unicode.IsSpace))
+				candidate = strings.TrimLeftFunc(word, unicode.IsSpace)
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// splitWords splits in words keeping the spaces, each CJK character is a word
+func splitWords(str string) []string {
+	var words []string
+	word := ""
+	for _, r := range str {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana):
+			if word != "" {
+				words = append(words, word)
+			}
+			words = append(words, string(r))
+			word = ""
+		case unicode.IsSpace(r):
+			word += string(r)
+			words = append(words, word)
+			word = ""
+		default:
+			word += string(r)
+		}
+	}
+	if word != "" {
+		words = append(words, word)
+	}
+	return words
+}
+
+// prepareText returns the face and the text, wrapped if needed, that fits the
+// style at the current layout
+func (g game) prepareText(str string, style textStyle) (text.Face, string, float64) {
+	size := style.size * g.layout.scale
+	face := g.fonts.face(size)
+	if style.wrap > 0 {
+		str = wrapText(str, face, float64(style.wrap))
+	}
+	lineSpacing := float64(size) * LINE_SPACING
+	if style.fit > 0 {
+		if w, _ := text.Measure(str, face, lineSpacing); w > float64(style.fit) {
+			size = size * style.fit / float32(w)
+			face = g.fonts.face(size)
+			lineSpacing = float64(size) * LINE_SPACING
+		}
+	}
+	return face, str, lineSpacing
+}
+
+// MeasureText returns the size of a text drawn with a style
+func (g game) MeasureText(str string, style textStyle) (float32, float32) {
+	face, str, lineSpacing := g.prepareText(str, style)
+	w, h := text.Measure(str, face, lineSpacing)
+	return float32(w), float32(h)
+}
+
+// DrawText draws a text aligned to x, y as the style says
+func (g game) DrawText(screen *ebiten.Image, str string, x, y float32, style textStyle) {
+	face, str, lineSpacing := g.prepareText(str, style)
+
+	draw := func(dx, dy float32, clr color.Color) {
+		op := &text.DrawOptions{}
+		op.LineSpacing = lineSpacing
+		op.PrimaryAlign = style.align
+		op.SecondaryAlign = style.verticalAlign
+		op.GeoM.Translate(float64(x+dx), float64(y+dy))
+		op.ColorScale.ScaleWithColor(clr)
+		text.Draw(screen, str, face, op)
+	}
+
+	if style.shadow > 0 {
+		offset := style.shadow * g.layout.scale
+		draw(offset, offset, style.shadowColor)
+	}
+	if style.outline > 0 {
+		width := style.outline * g.layout.scale
+		for i := 0; i < 8; i++ {
+			angle := float64(i) * math.Pi / 4
+			draw(width*float32(math.Cos(angle)), width*float32(math.Sin(angle)), style.outlineColor)
+		}
+	}
+	draw(0, 0, style.color)
+}
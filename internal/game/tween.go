@@ -0,0 +1,149 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import "math"
+
+const (
+	// seconds each animation takes, spawns are staggered tile by tile
+	SPAWN_TIME      = 0.4
+	SPAWN_STAGGER   = 0.015
+	HOVER_TIME      = 0.12
+	HOVER_SCALE     = 1.15
+	TETHER_TIME     = 0.4
+	TRANSITION_TIME = 0.3
+	BUTTON_STAGGER  = 0.05
+)
+
+// easing maps the linear progress of an animation, from 0 to 1, to a curve
+type easing func(t float32) float32
+
+func linear(t float32) float32 {
+	return t
+}
+
+func easeOutQuad(t float32) float32 {
+	return t * (2 - t)
+}
+
+func easeInOutCubic(t float32) float32 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+	f := -2*t + 2
+	return 1 - f*f*f/2
+}
+
+// easeOutBack goes a bit past the end and comes back, as popping in
+func easeOutBack(t float32) float32 {
+	const c1 = 1.70158
+	const c3 = c1 + 1
+	f := t - 1
+	return 1 + c3*f*f*f + c1*f*f
+}
+
+// tween animates a value between two numbers along some seconds of game time
+type tween struct {
+	from, to float32
+	start    float64
+	duration float64
+	ease     easing
+}
+
+func newTween(from, to float32, start, duration float64, ease easing) tween {
+	return tween{from: from, to: to, start: start, duration: duration, ease: ease}
+}
+
+// progress returns how far the tween is, from 0 before it starts to 1 when it
+// is done
+func (t tween) progress(now float64) float32 {
+	if t.duration <= 0 {
+		return 1
+	}
+	return float32(math.Max(0, math.Min(1, (now-t.start)/t.duration)))
+}
+
+func (t tween) value(now float64) float32 {
+	ease := t.ease
+	if ease == nil {
+		ease = linear
+	}
+	return t.from + (t.to-t.from)*ease(t.progress(now))
+}
+
+func (t tween) done(now float64) bool {
+	return t.progress(now) >= 1
+}
+
+// delayed returns the same tween starting some seconds later
+func (t tween) delayed(seconds float64) tween {
+	t.start += seconds
+	return t
+}
+
+// animation is something the timeline animates
+type animation int
+
+const (
+	SpawnAnimation animation = iota
+	HoverAnimation
+	TetherAnimation
+	StateAnimation
+	FlipAnimation
+	ExplainAnimation
+)
+
+// timeline keeps the tweens of what is being animated
+type timeline struct {
+	tweens map[animation]tween
+}
+
+func newTimeline() *timeline {
+	return &timeline{tweens: map[animation]tween{}}
+}
+
+func (tl *timeline) Start(a animation, t tween) {
+	tl.tweens[a] = t
+}
+
+// After starts an animation some seconds after another one ends, the start of
+// its tween is replaced, if the other one never started it is used as is
+func (tl *timeline) After(a, previous animation, delay float64, t tween) {
+	if p, ok := tl.tweens[previous]; ok {
+		t.start = p.start + p.duration
+	}
+	tl.tweens[a] = t.delayed(delay)
+}
+
+// Tween returns the tween of an animation, if it never started it is one that
+// is already done at 1
+func (tl *timeline) Tween(a animation) tween {
+	if t, ok := tl.tweens[a]; ok {
+		return t
+	}
+	return tween{from: 1, to: 1}
+}
+
+func (tl *timeline) Value(a animation, now float64) float32 {
+	return tl.Tween(a).value(now)
+}
@@ -0,0 +1,322 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"log"
+
+	"github.com/hajimehoshi/ebiten/v2"
+
+	"github.com/juan-medina/classical-concepts-2-trainer/internal/shapes"
+)
+
+type waymarkShape string
+
+const (
+	CircleWaymark waymarkShape = "circle"
+	SquareWaymark waymarkShape = "square"
+	// waymark setting that takes them from the strategy or the board
+	AUTO_WAYMARKS = "auto"
+	// size of the waymarks in tile radius units
+	WAYMARK_SIZE = 0.9
+	// storage key of the waymark sets the player can add
+	WAYMARKS_KEY = "waymarks"
+	// set made when a set of the game is edited in the settings
+	CUSTOM_WAYMARKS = "custom"
+)
+
+// labels the settings cycle through, the ones of the waymarks in the game
+var waymarkLabels = []string{"A", "B", "C", "D", "1", "2", "3", "4"}
+
+// waymark is the marker over a column, color is the index of the marker color
+// in the palette, so color blind palettes still apply
+type waymark struct {
+	Label string       `json:"label"`
+	Shape waymarkShape `json:"shape"`
+	Color int          `json:"color"`
+}
+
+// waymarkSet are the waymarks of the columns, from the first to the last, that
+// can be picked in the settings
+type waymarkSet struct {
+	ID    string               `json:"id"`
+	Marks [NUM_MARKERS]waymark `json:"marks"`
+	// if the player made it, so it is stored
+	custom bool
+}
+
+var (
+	letterWaymarks = [NUM_MARKERS]waymark{
+		{"A", CircleWaymark, 0}, {"B", CircleWaymark, 1}, {"C", CircleWaymark, 2}, {"D", CircleWaymark, 3},
+	}
+	numberWaymarks = [NUM_MARKERS]waymark{
+		{"1", SquareWaymark, 0}, {"2", SquareWaymark, 1}, {"3", SquareWaymark, 2}, {"4", SquareWaymark, 3},
+	}
+	reversedWaymarks = [NUM_MARKERS]waymark{
+		{"D", CircleWaymark, 3}, {"C", CircleWaymark, 2}, {"B", CircleWaymark, 1}, {"A", CircleWaymark, 0},
+	}
+)
+
+var waymarkSets = []waymarkSet{
+	{ID: "letters", Marks: letterWaymarks},
+	{ID: "numbers", Marks: numberWaymarks},
+	{ID: "reversed", Marks: reversedWaymarks},
+}
+
+// waymarks returns the waymark of each marker column, the ones in the settings
+// or, on auto, the ones of the strategy or else the ones of the board
+func (g game) waymarks() [NUM_MARKERS]waymark {
+	for _, set := range waymarkSets {
+		if set.ID == g.settings.Waymarks {
+			return set.Marks
+		}
+	}
+	if s, ok := g.strategy.(*declarativeStrategy); ok && s.Waymarks != nil {
+		return *s.Waymarks
+	}
+	return g.definition.waymarks
+}
+
+func validWaymarks(marks [NUM_MARKERS]waymark) error {
+	for _, w := range marks {
+		if w.Shape != CircleWaymark && w.Shape != SquareWaymark {
+			return fmt.Errorf("unknown waymark shape %q", w.Shape)
+		}
+		if w.Color < 0 || w.Color >= NUM_MARKERS {
+			return fmt.Errorf("waymark color %d out of range", w.Color)
+		}
+	}
+	return nil
+}
+
+func parseWaymarkSets(data []byte) ([]waymarkSet, error) {
+	var sets []waymarkSet
+	if err := json.Unmarshal(data, &sets); err != nil {
+		return nil, err
+	}
+	for _, set := range sets {
+		if set.ID == "" || set.ID == AUTO_WAYMARKS {
+			return nil, fmt.Errorf("waymark set without a valid id")
+		}
+		if err := validWaymarks(set.Marks); err != nil {
+			return nil, fmt.Errorf("waymark set %q: %w", set.ID, err)
+		}
+	}
+	return sets, nil
+}
+
+// loadWaymarks adds the waymark sets the player may have stored, replacing the
+// ones with the same id, they are skipped if they are not right
+func loadWaymarks() {
+	data, err := readStorage(WAYMARKS_KEY)
+	if err != nil {
+		return
+	}
+	sets, err := parseWaymarkSets(data)
+	if err != nil {
+		log.Printf("ignoring invalid waymarks: %v", err)
+		return
+	}
+	for _, set := range sets {
+		set.custom = true
+		replaced := false
+		for i := range waymarkSets {
+			if waymarkSets[i].ID == set.ID {
+				waymarkSets[i] = set
+				replaced = true
+			}
+		}
+		if !replaced {
+			waymarkSets = append(waymarkSets, set)
+		}
+	}
+}
+
+// saveWaymarks stores the sets the player made
+func saveWaymarks() {
+	var custom []waymarkSet
+	for _, set := range waymarkSets {
+		if set.custom {
+			custom = append(custom, set)
+		}
+	}
+	data, err := json.Marshal(custom)
+	if err != nil {
+		log.Printf("can not encode waymarks: %v", err)
+		return
+	}
+	if err := writeStorage(WAYMARKS_KEY, data); err != nil {
+		log.Printf("can not save waymarks: %v", err)
+	}
+}
+
+// editableWaymarks returns the set in use to be edited, a set of the game is
+// copied first to the custom set, so it is not changed
+func (g *game) editableWaymarks() *waymarkSet {
+	for i := range waymarkSets {
+		if waymarkSets[i].ID == g.settings.Waymarks && waymarkSets[i].custom {
+			return &waymarkSets[i]
+		}
+	}
+	set := waymarkSet{ID: CUSTOM_WAYMARKS, Marks: g.waymarks(), custom: true}
+	g.settings.Waymarks = CUSTOM_WAYMARKS
+	for i := range waymarkSets {
+		if waymarkSets[i].ID == CUSTOM_WAYMARKS {
+			waymarkSets[i] = set
+			return &waymarkSets[i]
+		}
+	}
+	waymarkSets = append(waymarkSets, set)
+	return &waymarkSets[len(waymarkSets)-1]
+}
+
+// EditWaymark changes the waymark being edited and stores the set
+func (g *game) EditWaymark(edit func(marks *[NUM_MARKERS]waymark, w *waymark)) {
+	set := g.editableWaymarks()
+	edit(&set.Marks, &set.Marks[g.waymarkEdit])
+	saveWaymarks()
+}
+
+// WaymarkEntries are the settings to edit the waymarks, one at a time, shown
+// when a set is picked
+func (g *game) WaymarkEntries() []menuEntry {
+	if g.settings.Waymarks == AUTO_WAYMARKS {
+		return nil
+	}
+	current := func() waymark {
+		return g.waymarks()[g.waymarkEdit]
+	}
+	return []menuEntry{
+		{
+			label: func() string { return g.T(EditWaymarkMessage) + ": " + current().Label },
+			change: func(delta int) {
+				g.waymarkEdit = (g.waymarkEdit + delta + NUM_MARKERS) % NUM_MARKERS
+			},
+		},
+		{
+			label: func() string { return g.T(WaymarkLabelMessage) + ": " + current().Label },
+			change: func(delta int) {
+				g.EditWaymark(func(_ *[NUM_MARKERS]waymark, w *waymark) {
+					index := 0
+					for i, l := range waymarkLabels {
+						if l == w.Label {
+							index = i
+						}
+					}
+					w.Label = waymarkLabels[(index+delta+len(waymarkLabels))%len(waymarkLabels)]
+				})
+			},
+		},
+		{
+			label: func() string {
+				if current().Shape == SquareWaymark {
+					return g.T(WaymarkShapeMessage) + ": " + g.T(SquareMessage)
+				}
+				return g.T(WaymarkShapeMessage) + ": " + g.T(CircleMessage)
+			},
+			change: func(int) {
+				g.EditWaymark(func(_ *[NUM_MARKERS]waymark, w *waymark) {
+					if w.Shape == CircleWaymark {
+						w.Shape = SquareWaymark
+					} else {
+						w.Shape = CircleWaymark
+					}
+				})
+			},
+		},
+		{
+			label: func() string { return fmt.Sprintf("%s: %d", g.T(WaymarkColorMessage), current().Color+1) },
+			change: func(delta int) {
+				g.EditWaymark(func(_ *[NUM_MARKERS]waymark, w *waymark) {
+					w.Color = (w.Color + delta + NUM_MARKERS) % NUM_MARKERS
+				})
+			},
+		},
+		{
+			label: func() string { return fmt.Sprintf("%s: %d", g.T(WaymarkPositionMessage), g.waymarkEdit+1) },
+			change: func(delta int) {
+				g.EditWaymark(func(marks *[NUM_MARKERS]waymark, _ *waymark) {
+					other := (g.waymarkEdit + delta + NUM_MARKERS) % NUM_MARKERS
+					marks[g.waymarkEdit], marks[other] = marks[other], marks[g.waymarkEdit]
+					g.waymarkEdit = other
+				})
+			},
+		},
+	}
+}
+
+func (g game) waymarkColor(column int) color.RGBA64 {
+	return g.palette.markers[g.waymarks()[column].Color%NUM_MARKERS]
+}
+
+func (g game) columnColor(column int) color.RGBA64 {
+	return g.palette.columns[g.waymarks()[column].Color%NUM_MARKERS]
+}
+
+// DrawWaymark draws a waymark as in the game, a glowing outline of its shape
+// and color, a faint fill and its label
+func (g game) DrawWaymark(screen *ebiten.Image, x, y, size float32, column int) {
+	w := g.waymarks()[column]
+	clr := g.waymarkColor(column)
+	key := spriteKey{kind: WaymarkSprite, size: size, stroke: clr, label: w.Label, shape: w.Shape}
+	drawSprite(screen, g.sprites.Get(key, g.waymarkExtent(w, size), func(img *ebiten.Image, x, y float32) {
+		g.drawWaymark(img, x, y, size, w, clr)
+	}), x, y, 1, 0)
+}
+
+func waymarkStyle(clr color.RGBA64) textStyle {
+	style := defaultTextStyle()
+	style.size = SMALL_FONT_SIZE
+	style.color = clr
+	style.outline = 2
+	return style
+}
+
+// waymarkExtent returns how far from its center a waymark draws, its glow or
+// its label if it is wider
+func (g game) waymarkExtent(w waymark, size float32) float32 {
+	width, height := g.MeasureText(w.Label, waymarkStyle(white))
+	return max(size*1.2+size/3, width/2, height/2) + 4
+}
+
+func (g game) drawWaymark(dst *ebiten.Image, x, y, size float32, w waymark, clr color.RGBA64) {
+	fill := clr
+	fill.R, fill.G, fill.B, fill.A = fill.R/4, fill.G/4, fill.B/4, fill.A/4
+
+	shape := shapes.Circle(x, y, size)
+	if w.Shape == SquareWaymark {
+		shape = shapes.RoundedPolygon(x, y, size*1.2, 4, 45, size/5)
+	}
+	shape.Draw(dst, shapes.Style{
+		Fill:        fill,
+		Stroke:      clr,
+		StrokeWidth: size / 8,
+		Glow:        clr,
+		GlowWidth:   size / 3,
+		AntiAlias:   true,
+	})
+	g.DrawText(dst, w.Label, x, y, waymarkStyle(clr))
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package shapes
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// most vertices a single draw call can index with uint16
+const MAX_BATCH_VERTICES = math.MaxUint16 + 1
+
+// Batch collects filled polygons and draws them together, the buffers are kept
+// between flushes so drawing every frame does not allocate
+type Batch struct {
+	vertices []ebiten.Vertex
+	indices  []uint16
+	// where each group of vertices that fits in a draw call starts
+	chunks []batchChunk
+}
+
+type batchChunk struct {
+	vertex, index int
+}
+
+// AddPolygon queues a regular polygon, like DrawPolygon does but without
+// drawing it yet
+func (b *Batch) AddPolygon(centerX, centerY, radius float32, sides int, rotation float32, clr color.Color) {
+	if sides < 3 {
+		return
+	}
+	base := b.reserve(sides)
+
+	r, g, bl, a := clr.RGBA()
+	cr, cg, cb, ca := float32(r)/0xffff, float32(g)/0xffff, float32(bl)/0xffff, float32(a)/0xffff
+
+	angle := float32(rotation * math.Pi / 180.0)
+	angleStep := 360.0 / float32(sides) * math.Pi / 180.0
+	for i := 0; i < sides; i++ {
+		x, y := getXYFromCenterWithAngleRadius(centerX, centerY, angle, radius)
+		b.vertices = append(b.vertices, ebiten.Vertex{
+			DstX: x, DstY: y, SrcX: 1, SrcY: 1,
+			ColorR: cr, ColorG: cg, ColorB: cb, ColorA: ca,
+		})
+		angle += angleStep
+	}
+	// a fan from the first vertex, fine as regular polygons are convex
+	for i := 1; i < sides-1; i++ {
+		b.indices = append(b.indices, base, base+uint16(i), base+uint16(i+1))
+	}
+}
+
+// reserve starts a new chunk if count vertices do not fit in the current one
+// and returns the index the next vertex will have in its chunk
+func (b *Batch) reserve(count int) uint16 {
+	if len(b.chunks) == 0 || len(b.vertices)-b.chunks[len(b.chunks)-1].vertex+count > MAX_BATCH_VERTICES {
+		b.chunks = append(b.chunks, batchChunk{vertex: len(b.vertices), index: len(b.indices)})
+	}
+	return uint16(len(b.vertices) - b.chunks[len(b.chunks)-1].vertex)
+}
+
+// Flush draws everything queued, in the order it was added, and empties the
+// batch
+func (b *Batch) Flush(dst *ebiten.Image) {
+	for i, chunk := range b.chunks {
+		vertexEnd, indexEnd := len(b.vertices), len(b.indices)
+		if i+1 < len(b.chunks) {
+			vertexEnd, indexEnd = b.chunks[i+1].vertex, b.chunks[i+1].index
+		}
+		dst.DrawTriangles(b.vertices[chunk.vertex:vertexEnd], b.indices[chunk.index:indexEnd], whiteSubImage, &ebiten.DrawTrianglesOptions{
+			ColorScaleMode: ebiten.ColorScaleModePremultipliedAlpha,
+		})
+	}
+	b.vertices = b.vertices[:0]
+	b.indices = b.indices[:0]
+	b.chunks = b.chunks[:0]
+}
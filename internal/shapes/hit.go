@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package shapes
+
+import "math"
+
+// Contains tells if a point is inside the shape, using the even-odd rule so
+// concave shapes like stars work too
+func (s Shape) Contains(x, y float32) bool {
+	inside := false
+	for i, j := 0, len(s)-1; i < len(s); j, i = i, i+1 {
+		a, b := s[i], s[j]
+		if (a.Y > y) != (b.Y > y) && x < (b.X-a.X)*(y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// Bounds returns the smallest axis aligned box that has the whole shape
+func (s Shape) Bounds() (minX, minY, maxX, maxY float32) {
+	if len(s) == 0 {
+		return
+	}
+	minX, minY, maxX, maxY = s[0].X, s[0].Y, s[0].X, s[0].Y
+	for _, p := range s[1:] {
+		minX = min(minX, p.X)
+		minY = min(minY, p.Y)
+		maxX = max(maxX, p.X)
+		maxY = max(maxY, p.Y)
+	}
+	return
+}
+
+// Index finds the shape under a point, shapes are kept in the cells of a grid
+// they touch so only the ones near the point get tested
+type Index struct {
+	cellSize float32
+	shapes   []Shape
+	ids      []int
+	cells    map[indexCell][]int
+}
+
+type indexCell struct {
+	x, y int
+}
+
+func NewIndex(cellSize float32) *Index {
+	return &Index{
+		cellSize: cellSize,
+		cells:    map[indexCell][]int{},
+	}
+}
+
+// Add puts a shape in the index with the id Find gives for it
+func (i *Index) Add(id int, shape Shape) {
+	n := len(i.shapes)
+	i.shapes = append(i.shapes, shape)
+	i.ids = append(i.ids, id)
+
+	minX, minY, maxX, maxY := shape.Bounds()
+	from, to := i.cell(minX, minY), i.cell(maxX, maxY)
+	for cx := from.x; cx <= to.x; cx++ {
+		for cy := from.y; cy <= to.y; cy++ {
+			cell := indexCell{cx, cy}
+			i.cells[cell] = append(i.cells[cell], n)
+		}
+	}
+}
+
+// Find returns the shape that has the point, if many do the last one added,
+// as it is the one drawn on top
+func (i *Index) Find(x, y float32) (int, bool) {
+	shapes := i.cells[i.cell(x, y)]
+	for n := len(shapes) - 1; n >= 0; n-- {
+		if i.shapes[shapes[n]].Contains(x, y) {
+			return i.ids[shapes[n]], true
+		}
+	}
+	return 0, false
+}
+
+func (i *Index) cell(x, y float32) indexCell {
+	return indexCell{
+		x: int(math.Floor(float64(x / i.cellSize))),
+		y: int(math.Floor(float64(y / i.cellSize))),
+	}
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package shapes
+
+import "math"
+
+type Point struct {
+	X, Y float32
+}
+
+// Shape is a closed outline, the last point joins back to the first one
+type Shape []Point
+
+// circles and rounded corners use one segment each this many pixels
+const CURVE_STEP = 4
+
+func Polygon(centerX, centerY, radius float32, sides int, rotation float32) Shape {
+	centerAngle := float32(rotation * math.Pi / 180.0)
+	angleStep := 360.0 / float32(sides) * math.Pi / 180.0
+
+	shape := make(Shape, 0, sides)
+	for i := 0; i < sides; i++ {
+		x, y := getXYFromCenterWithAngleRadius(centerX, centerY, centerAngle, radius)
+		shape = append(shape, Point{x, y})
+		centerAngle += angleStep
+	}
+	return shape
+}
+
+func Circle(centerX, centerY, radius float32) Shape {
+	return Polygon(centerX, centerY, radius, curveSegments(2*math.Pi*radius), 0)
+}
+
+// Star has points tips at the outer radius and the valleys between them at the
+// inner radius
+func Star(centerX, centerY, outerRadius, innerRadius float32, points int, rotation float32) Shape {
+	angle := float32(rotation * math.Pi / 180.0)
+	angleStep := float32(math.Pi) / float32(points)
+
+	shape := make(Shape, 0, points*2)
+	for i := 0; i < points*2; i++ {
+		radius := outerRadius
+		if i%2 == 1 {
+			radius = innerRadius
+		}
+		x, y := getXYFromCenterWithAngleRadius(centerX, centerY, angle, radius)
+		shape = append(shape, Point{x, y})
+		angle += angleStep
+	}
+	return shape
+}
+
+// Arrow goes from one point to another with the tip of its head in the second
+// one, width is the one of the shaft and the head is twice as wide
+func Arrow(fromX, fromY, toX, toY, width, headLength float32) Shape {
+	dx, dy := toX-fromX, toY-fromY
+	length := float32(math.Hypot(float64(dx), float64(dy)))
+	if length == 0 {
+		return nil
+	}
+	ux, uy := dx/length, dy/length
+	// perpendicular to the arrow
+	px, py := -uy, ux
+
+	headLength = float32(math.Min(float64(headLength), float64(length)))
+	baseX, baseY := toX-ux*headLength, toY-uy*headLength
+	shaft := width / 2
+	head := width
+
+	return Shape{
+		{fromX + px*shaft, fromY + py*shaft},
+		{baseX + px*shaft, baseY + py*shaft},
+		{baseX + px*head, baseY + py*head},
+		{toX, toY},
+		{baseX - px*head, baseY - py*head},
+		{baseX - px*shaft, baseY - py*shaft},
+		{fromX - px*shaft, fromY - py*shaft},
+	}
+}
+
+func RoundedPolygon(centerX, centerY, radius float32, sides int, rotation, cornerRadius float32) Shape {
+	return Polygon(centerX, centerY, radius, sides, rotation).Rounded(cornerRadius)
+}
+
+// Rounded returns the shape with every corner replaced by a curve that starts
+// and ends cornerRadius away from it, or before if the sides are too short
+func (s Shape) Rounded(cornerRadius float32) Shape {
+	if cornerRadius <= 0 || len(s) < 3 {
+		return s
+	}
+
+	result := make(Shape, 0, len(s)*4)
+	for i, corner := range s {
+		prev := s[(i+len(s)-1)%len(s)]
+		next := s[(i+1)%len(s)]
+
+		start := towards(corner, prev, cornerRadius)
+		end := towards(corner, next, cornerRadius)
+
+		// quadratic curve with the corner as control point
+		segments := curveSegments(cornerRadius * 2)
+		for j := 0; j <= segments; j++ {
+			t := float32(j) / float32(segments)
+			a, b, c := (1-t)*(1-t), 2*(1-t)*t, t*t
+			result = append(result, Point{
+				a*start.X + b*corner.X + c*end.X,
+				a*start.Y + b*corner.Y + c*end.Y,
+			})
+		}
+	}
+	return result
+}
+
+// Center returns the average of the points of the shape
+func (s Shape) Center() Point {
+	var center Point
+	for _, p := range s {
+		center.X += p.X
+		center.Y += p.Y
+	}
+	if len(s) > 0 {
+		center.X /= float32(len(s))
+		center.Y /= float32(len(s))
+	}
+	return center
+}
+
+// towards returns the point some distance from one to another, but never past
+// the middle of them
+func towards(from, to Point, distance float32) Point {
+	dx, dy := to.X-from.X, to.Y-from.Y
+	length := float32(math.Hypot(float64(dx), float64(dy)))
+	if length == 0 {
+		return from
+	}
+	distance = float32(math.Min(float64(distance), float64(length/2)))
+	return Point{from.X + dx/length*distance, from.Y + dy/length*distance}
+}
+
+func curveSegments(length float32) int {
+	return max(8, int(length/CURVE_STEP))
+}
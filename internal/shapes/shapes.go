@@ -28,7 +28,6 @@ import (
 	"math"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 var (
@@ -49,29 +48,10 @@ func getXYFromCenterWithAngleRadius(centerX, centerY, angle, radius float32) (fl
 	return centerX + radius*float32(math.Cos(float64(angle))), centerY + radius*float32(math.Sin(float64(angle)))
 }
 
-func DrawPolygon(dst *ebiten.Image, centerX float32, centerY float32, radius float32, sides int, rotation float32, color color.Color) {
-	centerAngle := float32(rotation * math.Pi / 180.0)
-	angleStep := 360.0 / float32(sides) * math.Pi / 180.0
-
-	var path = vector.Path{}
-	for i := 0; i < sides; i++ {
-		path.LineTo(getXYFromCenterWithAngleRadius(centerX, centerY, centerAngle, radius))
-		centerAngle += angleStep
-	}
-
-	vs, is := path.AppendVerticesAndIndicesForFilling(nil, nil)
+// single is reused to draw polygons one by one without allocating
+var single Batch
 
-	r, g, b, a := color.RGBA()
-	cr, cg, cb, ca := float32(r)/0xffff, float32(g)/0xffff, float32(b)/0xffff, float32(a)/0xffff
-
-	for i := range vs {
-		vs[i].SrcX = 1
-		vs[i].SrcY = 1
-		vs[i].ColorR = cr
-		vs[i].ColorG = cg
-		vs[i].ColorB = cb
-		vs[i].ColorA = ca
-	}
-
-	dst.DrawTriangles(vs, is, whiteSubImage, &ebiten.DrawTrianglesOptions{})
+func DrawPolygon(dst *ebiten.Image, centerX float32, centerY float32, radius float32, sides int, rotation float32, color color.Color) {
+	single.AddPolygon(centerX, centerY, radius, sides, rotation, color)
+	single.Flush(dst)
 }
@@ -0,0 +1,140 @@
+/*
+ * Copyright (c) 2023 Juan Antonio Medina Iglesias
+ *
+ *  Permission is hereby granted, free of charge, to any person obtaining a copy
+ *  of this software and associated documentation files (the "Software"), to deal
+ *  in the Software without restriction, including without limitation the rights
+ *  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+ *  copies of the Software, and to permit persons to whom the Software is
+ *  furnished to do so, subject to the following conditions:
+ *
+ *  The above copyright notice and this permission notice shall be included in
+ *  all copies or substantial portions of the Software.
+ *
+ *  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+ *  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+ *  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+ *  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+ *  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+ *  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+ *  THE SOFTWARE.
+ */
+
+package shapes
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// number of strokes a glow is made of, each one wider and fainter
+const GLOW_LAYERS = 6
+
+// Style is how a shape is painted, anything left nil is not drawn
+type Style struct {
+	Fill color.Color
+	// if set, the fill fades from Fill in the center to FillEdge in the outline
+	FillEdge    color.Color
+	Stroke      color.Color
+	StrokeWidth float32
+	// a halo around the shape that fades away GlowWidth pixels out
+	Glow      color.Color
+	GlowWidth float32
+	AntiAlias bool
+}
+
+// Draw paints the shape, first the glow, then the fill and then the stroke
+func (s Shape) Draw(dst *ebiten.Image, style Style) {
+	if len(s) < 3 {
+		return
+	}
+	if style.Glow != nil && style.GlowWidth > 0 {
+		s.drawGlow(dst, style.Glow, style.GlowWidth, style.AntiAlias)
+	}
+	if style.Fill != nil {
+		s.fill(dst, style.Fill, style.FillEdge, style.AntiAlias)
+	}
+	if style.Stroke != nil && style.StrokeWidth > 0 {
+		s.stroke(dst, style.Stroke, style.StrokeWidth, style.AntiAlias)
+	}
+}
+
+// fill draws a fan of triangles from the center of the shape, with the non
+// zero rule so it is right for concave shapes like stars and arrows
+func (s Shape) fill(dst *ebiten.Image, center, edge color.Color, antiAlias bool) {
+	if edge == nil {
+		edge = center
+	}
+
+	c := s.Center()
+	vs := make([]ebiten.Vertex, 0, len(s)+1)
+	is := make([]uint16, 0, len(s)*3)
+
+	vs = append(vs, vertex(c.X, c.Y, center))
+	for i, p := range s {
+		vs = append(vs, vertex(p.X, p.Y, edge))
+		next := (i+1)%len(s) + 1
+		is = append(is, 0, uint16(i+1), uint16(next))
+	}
+
+	dst.DrawTriangles(vs, is, whiteSubImage, &ebiten.DrawTrianglesOptions{
+		ColorScaleMode: ebiten.ColorScaleModePremultipliedAlpha,
+		FillRule:       ebiten.NonZero,
+		AntiAlias:      antiAlias,
+	})
+}
+
+func (s Shape) stroke(dst *ebiten.Image, clr color.Color, width float32, antiAlias bool) {
+	var path vector.Path
+	path.MoveTo(s[0].X, s[0].Y)
+	for _, p := range s[1:] {
+		path.LineTo(p.X, p.Y)
+	}
+	path.Close()
+
+	vs, is := path.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+		Width:    width,
+		LineJoin: vector.LineJoinRound,
+	})
+	for i := range vs {
+		vs[i] = vertex(vs[i].DstX, vs[i].DstY, clr)
+	}
+
+	dst.DrawTriangles(vs, is, whiteSubImage, &ebiten.DrawTrianglesOptions{
+		ColorScaleMode: ebiten.ColorScaleModePremultipliedAlpha,
+		AntiAlias:      antiAlias,
+	})
+}
+
+// drawGlow strokes the outline several times, wider and fainter each time, so
+// the overlapping strokes fade out from the shape
+func (s Shape) drawGlow(dst *ebiten.Image, clr color.Color, width float32, antiAlias bool) {
+	r, g, b, a := clr.RGBA()
+	layer := color.RGBA64{
+		R: uint16(r / GLOW_LAYERS),
+		G: uint16(g / GLOW_LAYERS),
+		B: uint16(b / GLOW_LAYERS),
+		A: uint16(a / GLOW_LAYERS),
+	}
+	for i := GLOW_LAYERS; i > 0; i-- {
+		s.stroke(dst, layer, width*2*float32(i)/GLOW_LAYERS, antiAlias)
+	}
+}
+
+// vertex keeps the color premultiplied, as RGBA gives it, so the triangles
+// are drawn in the premultiplied color scale mode
+func vertex(x, y float32, clr color.Color) ebiten.Vertex {
+	r, g, b, a := clr.RGBA()
+	return ebiten.Vertex{
+		DstX:   x,
+		DstY:   y,
+		SrcX:   1,
+		SrcY:   1,
+		ColorR: float32(r) / 0xffff,
+		ColorG: float32(g) / 0xffff,
+		ColorB: float32(b) / 0xffff,
+		ColorA: float32(a) / 0xffff,
+	}
+}